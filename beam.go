@@ -0,0 +1,138 @@
+package inp
+
+import (
+	"fmt"
+	"math"
+)
+
+// SectionProperties is geometrical properties of beam section in local
+// coordinates, where y is local 1-direction and z is local 2-direction.
+// Moments of inertia are calculated around centroid of section.
+type SectionProperties struct {
+	Area float64
+	Iyy  float64 // integral of z*z over area
+	Izz  float64 // integral of y*y over area
+	Iyz  float64 // integral of y*z over area
+	J    float64 // torsional constant
+	Ay   float64 // shear area in y direction
+	Az   float64 // shear area in z direction
+}
+
+// rectangle is part of section with centroid (y, z) and sizes a in y
+// direction and b in z direction. Negative sizes used for holes.
+type rectangle struct {
+	y, z, a, b float64
+}
+
+// rectanglesProperties return area and moments of inertia of section
+// builded from rectangles around centroid of section
+func rectanglesProperties(rs ...rectangle) (p SectionProperties) {
+	var sy, sz float64
+	for _, r := range rs {
+		area := r.a * r.b
+		p.Area += area
+		sy += area * r.y
+		sz += area * r.z
+	}
+	if p.Area == 0 {
+		return
+	}
+	yc, zc := sy/p.Area, sz/p.Area
+	for _, r := range rs {
+		area := r.a * r.b
+		dy, dz := r.y-yc, r.z-zc
+		p.Iyy += area*r.b*r.b/12 + area*dz*dz
+		p.Izz += area*r.a*r.a/12 + area*dy*dy
+		p.Iyz += area * dy * dz
+	}
+	return
+}
+
+// Properties return geometrical properties of beam section.
+// Torsional constant and shear areas of not circular sections are
+// approximations:
+//
+//	RECT - torsional constant by Roark formula, shear areas 5/6 of area.
+//	CIRC - shear areas 9/10 of area.
+//	PIPE - shear areas 1/2 of area.
+//	BOX  - torsional constant by Bredt formula, shear areas of walls.
+//	I    - torsional constant of open thin-walled section, shear areas
+//	       of web and 5/6 of flanges.
+//	GENERAL - for *BEAM SECTION shear areas are shear correction factor
+//	       multiplied to area, torsional constant is polar moment of inertia.
+func (b BeamSection) Properties() (p SectionProperties, err error) {
+	amount, ok := beamSectionDimensions[b.Section]
+	if !ok {
+		err = fmt.Errorf("not valid beam section type: %s", b.Section)
+		return
+	}
+	dimensions := b.dimensions()
+	if len(dimensions) < amount && b.Section != "GENERAL" {
+		err = fmt.Errorf("beam section %s must have %d dimensions", b.Section, amount)
+		return
+	}
+	d := append(append([]float64{}, dimensions...), make([]float64, amount)...)
+	switch b.Section {
+	case "RECT":
+		a, h := d[0], d[1]
+		p = rectanglesProperties(rectangle{a: a, b: h})
+		long, short := math.Max(a, h), math.Min(a, h)
+		p.J = long * math.Pow(short, 3) *
+			(1.0/3.0 - 0.21*short/long*(1-math.Pow(short/long, 4)/12))
+		p.Ay = 5.0 / 6.0 * p.Area
+		p.Az = p.Ay
+
+	case "CIRC":
+		ry, rz := d[0], d[1]
+		p.Area = math.Pi * ry * rz
+		p.Iyy = math.Pi * ry * math.Pow(rz, 3) / 4
+		p.Izz = math.Pi * rz * math.Pow(ry, 3) / 4
+		p.J = math.Pi * math.Pow(ry, 3) * math.Pow(rz, 3) / (ry*ry + rz*rz)
+		p.Ay = 0.9 * p.Area
+		p.Az = p.Ay
+
+	case "PIPE":
+		ro, t := d[0], d[1]
+		ri := ro - t
+		p.Area = math.Pi * (ro*ro - ri*ri)
+		p.Iyy = math.Pi * (math.Pow(ro, 4) - math.Pow(ri, 4)) / 4
+		p.Izz = p.Iyy
+		p.J = 2 * p.Iyy
+		p.Ay = 0.5 * p.Area
+		p.Az = p.Ay
+
+	case "BOX":
+		a, h, t1, t2, t3, t4 := d[0], d[1], d[2], d[3], d[4], d[5]
+		p = rectanglesProperties(
+			rectangle{a: a, b: h},
+			rectangle{y: (t3 - t1) / 2, z: (t4 - t2) / 2, a: -(a - t1 - t3), b: h - t2 - t4},
+		)
+		am := (a - (t1+t3)/2) * (h - (t2+t4)/2)
+		contour := (h-(t2+t4)/2)*(1/t1+1/t3) + (a-(t1+t3)/2)*(1/t2+1/t4)
+		p.J = 4 * am * am / contour
+		p.Ay = a * (t2 + t4)
+		p.Az = h * (t1 + t3)
+
+	case "I":
+		l, h, b1, b2, t1, t2, t3 := d[0], d[1], d[2], d[3], d[4], d[5], d[6]
+		p = rectanglesProperties(
+			rectangle{z: t1/2 - l, a: b1, b: t1},
+			rectangle{z: h - t2/2 - l, a: b2, b: t2},
+			rectangle{z: t1 + (h-t1-t2)/2 - l, a: t3, b: h - t1 - t2},
+		)
+		p.J = (b1*math.Pow(t1, 3) + b2*math.Pow(t2, 3) + (h-t1-t2)*math.Pow(t3, 3)) / 3
+		p.Ay = 5.0 / 6.0 * (b1*t1 + b2*t2)
+		p.Az = h * t3
+
+	case "GENERAL":
+		p.Area, p.Iyy, p.Iyz, p.Izz = d[0], d[1], d[2], d[3]
+		if b.General {
+			p.J = d[4]
+		} else {
+			p.J = p.Iyy + p.Izz
+			p.Ay = d[4] * p.Area
+			p.Az = p.Ay
+		}
+	}
+	return
+}
@@ -0,0 +1,437 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Types of contact pair
+const (
+	NodeToSurface    = "NODE TO SURFACE"
+	SurfaceToSurface = "SURFACE TO SURFACE"
+	Mortar           = "MORTAR"
+	LinMortar        = "LINMORTAR"
+	PgLinMortar      = "PGLINMORTAR"
+)
+
+// ContactPair
+//
+// Examples:
+//
+//	*CONTACT PAIR,INTERACTION=SI1,TYPE=SURFACE TO SURFACE
+//	SSLAV,SMAST
+//
+//	*CONTACT PAIR,INTERACTION=SI1,SMALL SLIDING,ADJUST=0.005,TYPE=SURFACE TO SURFACE
+//	SSLAV,SMAST
+//
+// First line:
+//
+//	*CONTACT PAIR
+//	Enter the parameter INTERACTION and TYPE and their values, and,
+//	if necessary, the parameters SMALL SLIDING and ADJUST.
+//
+// Following line:
+//
+//	Name of the slave surface (can be nodal or element face based).
+//	Name of the master surface (must be based on element faces).
+type ContactPair struct {
+	Interaction  string
+	Type         string // NODE TO SURFACE, SURFACE TO SURFACE, MORTAR, ...
+	SmallSliding bool
+	Adjust       string // node set or clearance value
+	Slave        string
+	Master       string
+}
+
+func (cp ContactPair) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*CONTACT PAIR, INTERACTION=%s", cp.Interaction)
+	if cp.Type != "" {
+		fmt.Fprintf(&buf, ", TYPE=%s", cp.Type)
+	}
+	if cp.SmallSliding {
+		fmt.Fprintf(&buf, ", SMALL SLIDING")
+	}
+	if cp.Adjust != "" {
+		fmt.Fprintf(&buf, ", ADJUST=%s", cp.Adjust)
+	}
+	fmt.Fprintf(&buf, "\n%s, %s\n", cp.Slave, cp.Master)
+	return buf.String()
+}
+
+func (f *Model) parseContactPair(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*CONTACT PAIR") {
+		return false, nil
+	}
+	var cp ContactPair
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "INTERACTION":
+			cp.Interaction = value
+		case "TYPE":
+			cp.Type = value
+		case "SMALL SLIDING":
+			cp.SmallSliding = true
+		case "ADJUST":
+			cp.Adjust = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid contact pair parameter: %s", part)
+			return
+		}
+	}
+	if len(block) < 2 {
+		err = fmt.Errorf("contact pair without surfaces: %s", block[0])
+		return
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid contact pair line: %s", line)
+			return
+		}
+		cp.Slave, cp.Master = fs[0], fs[1]
+		f.ContactPairs = append(f.ContactPairs, cp)
+	}
+	return true, nil
+}
+
+// Types of pressure-overclosure relationship
+const (
+	Exponential = "EXPONENTIAL"
+	Linear      = "LINEAR"
+	Tabular     = "TABULAR"
+	Tied        = "TIED"
+	Hard        = "HARD"
+)
+
+// SurfaceBehavior
+//
+// First line:
+//
+//	*SURFACE BEHAVIOR
+//	Enter the parameter PRESSURE-OVERCLOSURE and its value.
+//
+// Following line if PRESSURE-OVERCLOSURE=EXPONENTIAL:
+//
+//	c0.
+//	p0.
+//
+// Following line if PRESSURE-OVERCLOSURE=LINEAR or HARD:
+//
+//	slope K of the pressure-overclosure curve.
+//	sigma_inf (only for node-to-face contact).
+//	c0 (only for node-to-face contact).
+//
+// Following lines if PRESSURE-OVERCLOSURE=TABULAR:
+//
+//	Pressure.
+//	Overclosure.
+//
+// Following line if PRESSURE-OVERCLOSURE=TIED:
+//
+//	slope K of the pressure-overclosure curve.
+type SurfaceBehavior struct {
+	PressureOverclosure string
+
+	C0       float64 // EXPONENTIAL, LINEAR, HARD
+	P0       float64 // EXPONENTIAL
+	K        float64 // LINEAR, HARD, TIED
+	SigmaInf float64 // LINEAR, HARD
+
+	Table []PressureOverclosure // TABULAR
+}
+
+type PressureOverclosure struct {
+	Pressure    float64
+	Overclosure float64
+}
+
+func (sb SurfaceBehavior) String() string {
+	if sb.PressureOverclosure == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*SURFACE BEHAVIOR, PRESSURE-OVERCLOSURE=%s\n", sb.PressureOverclosure)
+	switch sb.PressureOverclosure {
+	case Exponential:
+		if sb.C0 != 0 || sb.P0 != 0 {
+			fmt.Fprintf(&buf, "%s\n", floatList(sb.C0, sb.P0))
+		}
+	case Linear, Hard:
+		if sb.K != 0 || sb.SigmaInf != 0 || sb.C0 != 0 {
+			fmt.Fprintf(&buf, "%s\n", floatList(sb.K, sb.SigmaInf, sb.C0))
+		}
+	case Tied:
+		if sb.K != 0 {
+			fmt.Fprintf(&buf, "%s\n", efmt.Sprint(sb.K))
+		}
+	case Tabular:
+		for _, t := range sb.Table {
+			fmt.Fprintf(&buf, "%s, %s\n",
+				efmt.Sprint(t.Pressure), efmt.Sprint(t.Overclosure))
+		}
+	}
+	return buf.String()
+}
+
+// Friction
+//
+// First line:
+//
+//	*FRICTION
+//
+// Following line:
+//
+//	mu (> 0), the friction coefficient.
+//	lambda (> 0), the stick slope.
+type Friction struct {
+	Mu         float64
+	StickSlope float64
+}
+
+func (fr Friction) String() string {
+	if fr.Mu == 0 && fr.StickSlope == 0 {
+		return ""
+	}
+	return fmt.Sprintf("*FRICTION\n%s\n", floatList(fr.Mu, fr.StickSlope))
+}
+
+// SurfaceInteraction
+//
+// Example:
+//
+//	*SURFACE INTERACTION,NAME=SI1
+//	*SURFACE BEHAVIOR,PRESSURE-OVERCLOSURE=LINEAR
+//	1.E7,3.
+//	*FRICTION
+//	0.2,1.E5
+//
+// First line:
+//
+//	*SURFACE INTERACTION
+//	Enter the parameter NAME and its value.
+type SurfaceInteraction struct {
+	Name     string
+	Behavior SurfaceBehavior
+	Friction Friction
+}
+
+func (si SurfaceInteraction) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*SURFACE INTERACTION, NAME=%s\n", si.Name)
+	fmt.Fprintf(&buf, "%s", si.Behavior)
+	fmt.Fprintf(&buf, "%s", si.Friction)
+	return buf.String()
+}
+
+func (f *Model) parseSurfaceInteraction(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SURFACE INTERACTION") {
+		return false, nil
+	}
+	var si SurfaceInteraction
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NAME":
+			si.Name = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid surface interaction parameter: %s", part)
+			return
+		}
+	}
+	if si.Name == "" {
+		err = fmt.Errorf("surface interaction without name")
+		return
+	}
+	f.SurfaceInteractions = append(f.SurfaceInteractions, si)
+	return true, nil
+}
+
+// lastSurfaceInteraction return surface interaction for the properties
+// *SURFACE BEHAVIOR, *FRICTION located after *SURFACE INTERACTION
+func (f *Model) lastSurfaceInteraction() (si *SurfaceInteraction, err error) {
+	if len(f.SurfaceInteractions) == 0 {
+		err = fmt.Errorf("property without *SURFACE INTERACTION")
+		return
+	}
+	return &f.SurfaceInteractions[len(f.SurfaceInteractions)-1], nil
+}
+
+func (f *Model) parseSurfaceBehavior(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SURFACE BEHAVIOR") {
+		return false, nil
+	}
+	si, err := f.lastSurfaceInteraction()
+	if err != nil {
+		return
+	}
+	var sb SurfaceBehavior
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "PRESSURE-OVERCLOSURE":
+			sb.PressureOverclosure = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid surface behavior parameter: %s", part)
+			return
+		}
+	}
+	for _, line := range block[1:] {
+		var vs []float64
+		vs, err = parseFloats(line)
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0, 0)
+		switch sb.PressureOverclosure {
+		case Exponential:
+			sb.C0, sb.P0 = vs[0], vs[1]
+		case Linear, Hard:
+			sb.K, sb.SigmaInf, sb.C0 = vs[0], vs[1], vs[2]
+		case Tied:
+			sb.K = vs[0]
+		case Tabular:
+			sb.Table = append(sb.Table, PressureOverclosure{
+				Pressure:    vs[0],
+				Overclosure: vs[1],
+			})
+		default:
+			err = fmt.Errorf("not valid pressure-overclosure: %s", sb.PressureOverclosure)
+			return
+		}
+	}
+	si.Behavior = sb
+	return true, nil
+}
+
+func (f *Model) parseFriction(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*FRICTION") {
+		return false, nil
+	}
+	si, err := f.lastSurfaceInteraction()
+	if err != nil {
+		return
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid friction: %s", strings.Join(block, "\n"))
+		return
+	}
+	vs, err := parseFloats(block[1])
+	if err != nil {
+		return
+	}
+	vs = append(vs, 0, 0)
+	si.Friction = Friction{Mu: vs[0], StickSlope: vs[1]}
+	return true, nil
+}
+
+// Gap
+//
+// Example:
+//
+//	*GAP,ELSET=G1
+//	0.,0.,0.,1.,,1.E5,0.1
+//
+// First line:
+//
+//	*GAP
+//	Enter the ELSET parameter and its value.
+//
+// Second line:
+//
+//	Clearance.
+//	First component of the gap direction (for GAPUNI elements).
+//	Second component of the gap direction (for GAPUNI elements).
+//	Third component of the gap direction (for GAPUNI elements).
+//	Not used.
+//	Gap stiffness k (default 1e12).
+//	Tensile force f_inf for large gaps (default 1e-3 k).
+type Gap struct {
+	Elset     string
+	Clearance float64
+	Direction [3]float64
+	K         float64
+	Finf      float64
+}
+
+func (g Gap) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*GAP, ELSET=%s\n", g.Elset)
+	fmt.Fprintf(&buf, "%s\n", floatList(g.Clearance,
+		g.Direction[0], g.Direction[1], g.Direction[2],
+		0, g.K, g.Finf))
+	return buf.String()
+}
+
+func (f *Model) parseGap(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*GAP") {
+		return false, nil
+	}
+	var g Gap
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ELSET":
+			g.Elset = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid gap parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid gap: %s", strings.Join(block, "\n"))
+		return
+	}
+	vs, err := parseFloats(block[1])
+	if err != nil {
+		return
+	}
+	vs = append(vs, make([]float64, 7)...)
+	g.Clearance = vs[0]
+	copy(g.Direction[:], vs[1:4])
+	g.K = vs[5]
+	g.Finf = vs[6]
+	f.Gaps = append(f.Gaps, g)
+	return true, nil
+}
+
+// ContactSurfaces return slave and master surfaces of contact pair
+// from the list of model surfaces.
+func (f Model) ContactSurfaces(cp ContactPair) (slave, master Surface, err error) {
+	slave, err = f.surface(cp.Slave)
+	if err != nil {
+		return
+	}
+	master, err = f.surface(cp.Master)
+	if err != nil {
+		return
+	}
+	if !master.IsElementType {
+		err = fmt.Errorf("master surface %s is not based on element faces", cp.Master)
+		return
+	}
+	return
+}
+
+// ContactInteraction return surface interaction of contact pair.
+func (f Model) ContactInteraction(cp ContactPair) (si SurfaceInteraction, err error) {
+	for _, si := range f.SurfaceInteractions {
+		if si.Name == cp.Interaction {
+			return si, nil
+		}
+	}
+	err = fmt.Errorf("not found surface interaction: %s", cp.Interaction)
+	return
+}
@@ -0,0 +1,366 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Restart
+//
+// Examples:
+//
+//	*RESTART,READ,STEP=1
+//
+//	*RESTART,WRITE,FREQUENCY=1
+//
+// First and only line:
+//
+//	*RESTART
+//	Enter the parameter READ or WRITE and, if necessary, the parameters
+//	STEP (only for READ), FREQUENCY (only for WRITE) and OVERLAY.
+type Restart struct {
+	Read      bool
+	Write     bool
+	Step      int // step to read, zero for the last step
+	Frequency int // frequency of writing in steps, zero for default 1
+	Overlay   bool
+}
+
+func (r Restart) String() string {
+	if !r.Read && !r.Write {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*RESTART")
+	if r.Read {
+		fmt.Fprintf(&buf, ", READ")
+	}
+	if r.Write {
+		fmt.Fprintf(&buf, ", WRITE")
+	}
+	if r.Step != 0 {
+		fmt.Fprintf(&buf, ", STEP=%d", r.Step)
+	}
+	if r.Frequency != 0 {
+		fmt.Fprintf(&buf, ", FREQUENCY=%d", r.Frequency)
+	}
+	if r.Overlay {
+		fmt.Fprintf(&buf, ", OVERLAY")
+	}
+	fmt.Fprintf(&buf, "\n")
+	return buf.String()
+}
+
+func parseRestart(r *Restart) func(block []string) (ok bool, err error) {
+	return func(block []string) (ok bool, err error) {
+		if !isHeader(block[0], "*RESTART") {
+			return false, nil
+		}
+		for _, part := range fields(block[0])[1:] {
+			key, value := keyValue(part)
+			switch key {
+			case "READ":
+				r.Read = true
+			case "WRITE":
+				r.Write = true
+			case "STEP":
+				r.Step, err = parseInt(value)
+			case "FREQUENCY":
+				r.Frequency, err = parseInt(value)
+			case "OVERLAY":
+				r.Overlay = true
+			case "":
+				// do nothing
+			default:
+				err = fmt.Errorf("not valid restart parameter: %s", part)
+			}
+			if err != nil {
+				return
+			}
+		}
+		if len(block) != 1 {
+			err = fmt.Errorf("restart have not data lines")
+			return
+		}
+		return true, nil
+	}
+}
+
+// PhysicalConstants
+//
+// Example:
+//
+//	*PHYSICAL CONSTANTS,ABSOLUTE ZERO=-273.15,STEFAN BOLTZMANN=5.669E-8
+//
+// First and only line:
+//
+//	*PHYSICAL CONSTANTS
+//	Enter at least one of the parameters ABSOLUTE ZERO,
+//	STEFAN BOLTZMANN and NEWTON GRAVITY and their values.
+type PhysicalConstants struct {
+	IsAbsoluteZero  bool // zero is valid value of absolute zero
+	AbsoluteZero    float64
+	StefanBoltzmann float64
+	NewtonGravity   float64
+}
+
+func (p PhysicalConstants) String() string {
+	var params []string
+	if p.IsAbsoluteZero {
+		params = append(params, "ABSOLUTE ZERO="+efmt.Sprint(p.AbsoluteZero))
+	}
+	if p.StefanBoltzmann != 0 {
+		params = append(params, "STEFAN BOLTZMANN="+efmt.Sprint(p.StefanBoltzmann))
+	}
+	if p.NewtonGravity != 0 {
+		params = append(params, "NEWTON GRAVITY="+efmt.Sprint(p.NewtonGravity))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("*PHYSICAL CONSTANTS, %s\n", strings.Join(params, ", "))
+}
+
+func (f *Model) parsePhysicalConstants(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*PHYSICAL CONSTANTS") {
+		return false, nil
+	}
+	p := &f.PhysicalConstants
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ABSOLUTE ZERO":
+			p.IsAbsoluteZero = true
+			p.AbsoluteZero, err = parseFloat(value)
+		case "STEFAN BOLTZMANN":
+			p.StefanBoltzmann, err = parseFloat(value)
+		case "NEWTON GRAVITY":
+			p.NewtonGravity, err = parseFloat(value)
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid physical constants parameter: %s", part)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if len(block) != 1 {
+		err = fmt.Errorf("physical constants have not data lines")
+		return
+	}
+	return true, nil
+}
+
+// Controls parameters
+const (
+	ControlsTimeIncrementation = "TIME INCREMENTATION"
+	ControlsField              = "FIELD"
+	ControlsContact            = "CONTACT"
+	ControlsLineSearch         = "LINE SEARCH"
+)
+
+// ConvergenceControls is parameters of
+// *CONTROLS,PARAMETERS=TIME INCREMENTATION for tuning of convergence
+// of nonlinear calculations. Zero value means CalculiX default.
+type ConvergenceControls struct {
+	I0 int // iteration after which the check is made whether the residuals increase in two consecutive iterations (default 4)
+	IR int // iteration after which the logarithmic convergence check is performed (default 8)
+	IP int // iteration after which the residual tolerance is increased (default 9)
+	IC int // maximum number of iterations allowed (default 16)
+	IL int // number of iterations after which the size of the subsequent increment will be reduced (default 10)
+	IG int // maximum number of iterations in two consecutive increments for increase of the increment size (default 4)
+	IS int // unused
+	IA int // maximum number of cutbacks per increment (default 5)
+	IJ int // unused
+	IT int // unused
+
+	Df float64 // cutback factor if the solution seems to diverge (default 0.25)
+	DC float64 // cutback factor if the logarithmic extrapolation predicts too many iterations (default 0.5)
+	DB float64 // cutback factor for the next increment if more than IL iterations were needed (default 0.75)
+	DA float64 // cutback factor if the temperature change in two subsequent increments exceeds DELTMX (default 0.85)
+	DS float64 // unused
+	DH float64 // unused
+	DD float64 // increase factor if the increment converged in fewer than IG iterations (default 1.5)
+	WG float64 // unused
+}
+
+// FieldControls is parameters of *CONTROLS,PARAMETERS=FIELD for
+// convergence criteria of field. Zero value means CalculiX default.
+type FieldControls struct {
+	Rn   float64 // convergence criterion for the ratio of the largest residual to the average force (default 0.005)
+	Cn   float64 // convergence criterion for the ratio of the largest solution correction to the largest incremental solution value (default 0.01)
+	Q0   float64 // initial value at the start of a new step of the time average force (default is time average force of the previous steps)
+	Qu   float64 // user-defined average force (default is calculated)
+	Rp   float64 // alternative residual convergence criterion if the iterations are not converging (default 0.02)
+	Eps  float64 // criterion for zero flux relative to average force (default 1e-5)
+	CEps float64 // criterion for zero increment of solution relative to largest incremental solution value (default 1e-3)
+	Rl   float64 // convergence criterion for the ratio of the largest residual to the average force for linear calculations (default 1e-8)
+}
+
+// ContactControls is parameters of *CONTROLS,PARAMETERS=CONTACT.
+// Zero value means CalculiX default.
+type ContactControls struct {
+	Delcon    float64 // maximum fraction of the contact surface for which the contact status may change (default 0.001)
+	Alea      float64 // fraction of the characteristic element length for node-to-face contact search (default 0.1)
+	KScaleMax int     // maximum number of increases of the penalty stiffness (default 100)
+	Itf2f     int     // maximum number of iterations in which the face-to-face contact elements are regenerated (default 60)
+}
+
+// LineSearchControls is parameters of *CONTROLS,PARAMETERS=LINE SEARCH.
+// Zero value means CalculiX default.
+type LineSearchControls struct {
+	Nls  float64 // unused
+	SMax float64 // maximum line search factor
+	SMin float64 // minimum line search factor
+}
+
+// Controls
+//
+// Examples:
+//
+//	*CONTROLS,PARAMETERS=TIME INCREMENTATION
+//	100,100,9,100,10,4,,5
+//	.25,.5,.75,.85,,,1.5,,
+//
+//	*CONTROLS,PARAMETERS=FIELD
+//	0.25,0.25,0.01,,0.02,1.E-5,1.E-3,1.E-8
+//
+//	*CONTROLS,RESET
+//
+// First line:
+//
+//	*CONTROLS
+//	Enter the parameter PARAMETERS and its value (TIME INCREMENTATION,
+//	FIELD, CONTACT or LINE SEARCH) or the parameter RESET.
+//
+// Following lines: values of parameters.
+type Controls struct {
+	Reset      bool // all control parameters are reset to default values
+	Parameters string
+
+	TimeIncrementation ConvergenceControls
+	Field              FieldControls
+	Contact            ContactControls
+	LineSearch         LineSearchControls
+}
+
+func (c Controls) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*CONTROLS")
+	if c.Reset {
+		fmt.Fprintf(&buf, ", RESET")
+	}
+	if c.Parameters != "" {
+		fmt.Fprintf(&buf, ", PARAMETERS=%s", c.Parameters)
+	}
+	fmt.Fprintf(&buf, "\n")
+	var lines []string
+	switch c.Parameters {
+	case ControlsTimeIncrementation:
+		t := c.TimeIncrementation
+		lines = []string{
+			givenList(float64(t.I0), float64(t.IR), float64(t.IP), float64(t.IC),
+				float64(t.IL), float64(t.IG), float64(t.IS), float64(t.IA),
+				float64(t.IJ), float64(t.IT)),
+			givenList(t.Df, t.DC, t.DB, t.DA, t.DS, t.DH, t.DD, t.WG),
+		}
+	case ControlsField:
+		fc := c.Field
+		lines = []string{givenList(fc.Rn, fc.Cn, fc.Q0, fc.Qu, fc.Rp, fc.Eps, fc.CEps, fc.Rl)}
+	case ControlsContact:
+		cc := c.Contact
+		lines = []string{givenList(cc.Delcon, cc.Alea, float64(cc.KScaleMax), float64(cc.Itf2f))}
+	case ControlsLineSearch:
+		ls := c.LineSearch
+		lines = []string{givenList(ls.Nls, ls.SMax, ls.SMin)}
+	}
+	for len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for _, line := range lines {
+		if line == "" {
+			// empty line is not parsed, so that line of default values
+			// is written as one empty field
+			line = ","
+		}
+		fmt.Fprintf(&buf, "%s\n", line)
+	}
+	return buf.String()
+}
+
+func (s *Step) parseControls(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*CONTROLS") {
+		return false, nil
+	}
+	var c Controls
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "RESET":
+			c.Reset = true
+		case "PARAMETERS":
+			c.Parameters = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid controls parameter: %s", part)
+			return
+		}
+	}
+	var lines [][]float64
+	for _, line := range block[1:] {
+		var vs []float64
+		if strings.Trim(line, ", ") != "" {
+			vs, err = parseFloats(line)
+			if err != nil {
+				return
+			}
+		}
+		lines = append(lines, vs)
+	}
+	// value return value of line by position, zero if not given
+	value := func(line, pos int) float64 {
+		if line < len(lines) && pos < len(lines[line]) {
+			return lines[line][pos]
+		}
+		return 0
+	}
+	switch c.Parameters {
+	case "":
+		if !c.Reset {
+			err = fmt.Errorf("controls must have parameter PARAMETERS or RESET")
+			return
+		}
+	case ControlsTimeIncrementation:
+		t := &c.TimeIncrementation
+		for i, p := range []*int{&t.I0, &t.IR, &t.IP, &t.IC, &t.IL, &t.IG, &t.IS, &t.IA, &t.IJ, &t.IT} {
+			*p = int(value(0, i))
+		}
+		for i, p := range []*float64{&t.Df, &t.DC, &t.DB, &t.DA, &t.DS, &t.DH, &t.DD, &t.WG} {
+			*p = value(1, i)
+		}
+	case ControlsField:
+		fc := &c.Field
+		for i, p := range []*float64{&fc.Rn, &fc.Cn, &fc.Q0, &fc.Qu, &fc.Rp, &fc.Eps, &fc.CEps, &fc.Rl} {
+			*p = value(0, i)
+		}
+	case ControlsContact:
+		cc := &c.Contact
+		cc.Delcon, cc.Alea = value(0, 0), value(0, 1)
+		cc.KScaleMax, cc.Itf2f = int(value(0, 2)), int(value(0, 3))
+	case ControlsLineSearch:
+		ls := &c.LineSearch
+		for i, p := range []*float64{&ls.Nls, &ls.SMax, &ls.SMin} {
+			*p = value(0, i)
+		}
+	default:
+		err = fmt.Errorf("not valid controls parameters: %s", c.Parameters)
+		return
+	}
+	s.Controls = append(s.Controls, c)
+	return true, nil
+}
@@ -17,6 +17,9 @@ func Convert(inpFilename, stdFilename string) (err error) {
 	if err != nil {
 		return err
 	}
+	// shell elements with not valid thickness are converted
+	// with zero thickness
+	thicknesses, _ := f.ElementThicknesses()
 	var std staad.Format
 	// convert
 	{
@@ -39,6 +42,7 @@ func Convert(inpFilename, stdFilename string) (err error) {
 				for i := 0; i < 3; i++ {
 					s.IPoint[i] = data.Nodes[i]
 				}
+				s.Thickness = thicknesses[data.Index]
 				std.Shells = append(std.Shells, s)
 			case 4, 8:
 				var s staad.Shell
@@ -47,6 +51,7 @@ func Convert(inpFilename, stdFilename string) (err error) {
 				for i := 0; i < 4; i++ {
 					s.IPoint[i] = data.Nodes[i]
 				}
+				s.Thickness = thicknesses[data.Index]
 				std.Shells = append(std.Shells, s)
 			default:
 				// TODO realize more flexibility
@@ -0,0 +1,320 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Coupling types
+const (
+	CouplingKinematic    = "KINEMATIC"
+	CouplingDistributing = "DISTRIBUTING"
+)
+
+// Coupling is constraint of keyword *COUPLING with sub-keyword
+// *KINEMATIC or *DISTRIBUTING.
+//
+// Examples:
+//
+//	*COUPLING,REF NODE=262,SURFACE=S1,CONSTRAINT NAME=CN1
+//	*KINEMATIC
+//	1,3
+//
+//	*COUPLING,REF NODE=262,SURFACE=S1,ORIENTATION=OR1,CONSTRAINT NAME=CN1
+//	*DISTRIBUTING
+//	6,6
+//
+// First line:
+//
+//	*COUPLING
+//	Enter the parameters REF NODE, SURFACE and CONSTRAINT NAME and their
+//	values and, if necessary, the parameter ORIENTATION.
+//
+// Second line:
+//
+//	*KINEMATIC or *DISTRIBUTING
+//
+// Following line:
+//
+//	First degree of freedom.
+//	Last degree of freedom (optional).
+//
+// Weights of surface nodes of *DISTRIBUTING are not input data of
+// *COUPLING. For coupling with weights of nodes use DistributingCoupling.
+type Coupling struct {
+	ConstraintName string
+	RefNode        int
+	Surface        string
+	Orientation    string
+	Type           string   // KINEMATIC or DISTRIBUTING
+	Dofs           [][2]int // first and last degree of freedom, last is zero if not given
+}
+
+func (c Coupling) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*COUPLING, REF NODE=%d, SURFACE=%s", c.RefNode, c.Surface)
+	if c.Orientation != "" {
+		fmt.Fprintf(&buf, ", ORIENTATION=%s", c.Orientation)
+	}
+	fmt.Fprintf(&buf, ", CONSTRAINT NAME=%s\n", c.ConstraintName)
+	if c.Type == "" {
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "*%s\n", c.Type)
+	for _, d := range c.Dofs {
+		if d[1] == 0 {
+			fmt.Fprintf(&buf, "%d\n", d[0])
+			continue
+		}
+		fmt.Fprintf(&buf, "%d, %d\n", d[0], d[1])
+	}
+	return buf.String()
+}
+
+func (f *Model) parseCoupling(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*COUPLING") {
+		return false, nil
+	}
+	var c Coupling
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "REF NODE":
+			c.RefNode, err = parseInt(value)
+		case "SURFACE":
+			c.Surface = value
+		case "ORIENTATION":
+			c.Orientation = value
+		case "CONSTRAINT NAME":
+			c.ConstraintName = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid coupling parameter: %s", part)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if len(block) != 1 {
+		err = fmt.Errorf("coupling have not data lines")
+		return
+	}
+	f.Couplings = append(f.Couplings, c)
+	return true, nil
+}
+
+// parseCouplingType parse sub-keywords *KINEMATIC and *DISTRIBUTING
+// located after *COUPLING
+func (f *Model) parseCouplingType(block []string) (ok bool, err error) {
+	var typ string
+	switch {
+	case isHeader(block[0], "*KINEMATIC"):
+		typ = CouplingKinematic
+	case isHeader(block[0], "*DISTRIBUTING"):
+		typ = CouplingDistributing
+	default:
+		return false, nil
+	}
+	if len(f.Couplings) == 0 {
+		err = fmt.Errorf("*%s without *COUPLING", typ)
+		return
+	}
+	c := &f.Couplings[len(f.Couplings)-1]
+	c.Type = typ
+	for _, part := range fields(block[0])[1:] {
+		if key, _ := keyValue(part); key != "" {
+			err = fmt.Errorf("not valid %s parameter: %s", typ, part)
+			return
+		}
+	}
+	for _, line := range block[1:] {
+		fs := append(fields(line), "")
+		var d [2]int
+		if d[0], err = parseInt(fs[0]); err != nil {
+			return
+		}
+		if fs[1] != "" {
+			if d[1], err = parseInt(fs[1]); err != nil {
+				return
+			}
+		}
+		c.Dofs = append(c.Dofs, d)
+	}
+	return true, nil
+}
+
+// AddCoupling add new node in reference point and coupling of that node
+// with surface. Type of coupling is KINEMATIC or DISTRIBUTING. All 6
+// degrees of freedom are constrained.
+func (f *Model) AddCoupling(name string, point [3]float64, surface, typ string) (c Coupling, err error) {
+	if typ != CouplingKinematic && typ != CouplingDistributing {
+		err = fmt.Errorf("not valid coupling type: %s", typ)
+		return
+	}
+	if _, err = f.surface(surface); err != nil {
+		return
+	}
+	var index int
+	for _, n := range f.Nodes {
+		if index < n.Index {
+			index = n.Index
+		}
+	}
+	index++
+	f.Nodes = append(f.Nodes, Node{Index: index, Coord: point})
+	c = Coupling{
+		ConstraintName: name,
+		RefNode:        index,
+		Surface:        surface,
+		Type:           typ,
+		Dofs:           [][2]int{{1, 6}},
+	}
+	f.Couplings = append(f.Couplings, c)
+	return
+}
+
+// CouplingWeight is node with weight of distributing coupling
+type CouplingWeight struct {
+	Node   string // node number or node set
+	Weight float64
+}
+
+// DistributingCoupling
+//
+// Example:
+//
+//	*ELEMENT,TYPE=DCOUP3D,ELSET=E1
+//	33,262
+//	*DISTRIBUTING COUPLING,ELSET=E1
+//	LOAD,1.
+//
+// First line:
+//
+//	*DISTRIBUTING COUPLING
+//	Enter the ELSET parameter and its value. Element set contains
+//	one element of type DCOUP3D with reference node.
+//
+// Following line:
+//
+//	Node number or node set
+//	Weight
+//
+// Repeat this line if needed.
+type DistributingCoupling struct {
+	ElsetName string
+	Weights   []CouplingWeight
+
+	// Deprecated: use Model.AddDistributingCoupling for element of
+	// type DCOUP3D. If ElsetNode is not zero, then element ElsetNode
+	// with ReferenceNode is written after coupling.
+	ElsetNode     int
+	ReferenceNode int
+
+	// Deprecated: use Weights. Nodes are written with weight 1.0.
+	NodeIndexes []int
+	NodeNames   []string
+}
+
+func (d DistributingCoupling) String() string {
+	if d.ElsetName == "" {
+		return "\n"
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*DISTRIBUTING COUPLING, ELSET=%s\n", d.ElsetName)
+	for _, w := range d.Weights {
+		fmt.Fprintf(&buf, "%s, %s\n", w.Node, efmt.Sprint(w.Weight))
+	}
+	for _, n := range d.NodeIndexes {
+		fmt.Fprintf(&buf, "%d,1.\n", n)
+	}
+	for _, n := range d.NodeNames {
+		fmt.Fprintf(&buf, "%s,1.\n", n)
+	}
+	if d.ElsetNode == 0 {
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "*ELSET,ELSET=%s\n", d.ElsetName)
+	fmt.Fprintf(&buf, "%d\n", d.ElsetNode)
+	fmt.Fprintf(&buf, "*ELEMENT,TYPE=DCOUP3D\n")
+	fmt.Fprintf(&buf, "%d, %d\n", d.ElsetNode, d.ReferenceNode)
+	return buf.String()
+}
+
+func (f *Model) parseDistributingCoupling(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*DISTRIBUTING COUPLING") {
+		return false, nil
+	}
+	var d DistributingCoupling
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ELSET":
+			d.ElsetName = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid distributing coupling parameter: %s", part)
+			return
+		}
+	}
+	if d.ElsetName == "" {
+		err = fmt.Errorf("distributing coupling must have parameter ELSET")
+		return
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid distributing coupling line: %s", line)
+			return
+		}
+		w := CouplingWeight{Node: fs[0]}
+		if w.Weight, err = parseFloat(fs[1]); err != nil {
+			return
+		}
+		d.Weights = append(d.Weights, w)
+	}
+	f.DistributingCouplings = append(f.DistributingCouplings, d)
+	return true, nil
+}
+
+// AddDistributingCoupling add new node in reference point, element of
+// type DCOUP3D with that node in element set elset and distributing
+// coupling of that element with weighted nodes.
+func (f *Model) AddDistributingCoupling(elset string, point [3]float64, weights []CouplingWeight) (d DistributingCoupling, err error) {
+	if len(weights) == 0 {
+		err = fmt.Errorf("distributing coupling without weights")
+		return
+	}
+	for _, w := range weights {
+		if _, err = f.nodeLocation(w.Node); err != nil {
+			return
+		}
+	}
+	var node, element int
+	for _, n := range f.Nodes {
+		if node < n.Index {
+			node = n.Index
+		}
+	}
+	for _, el := range f.Elements {
+		if element < el.Index {
+			element = el.Index
+		}
+	}
+	f.Nodes = append(f.Nodes, Node{Index: node + 1, Coord: point})
+	f.Elements = append(f.Elements, Element{
+		Type:  "DCOUP3D",
+		Elset: elset,
+		Index: element + 1,
+		Nodes: []int{node + 1},
+	})
+	d = DistributingCoupling{
+		ElsetName: elset,
+		Weights:   append([]CouplingWeight{}, weights...),
+	}
+	f.DistributingCouplings = append(f.DistributingCouplings, d)
+	return
+}
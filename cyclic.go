@@ -0,0 +1,301 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// CyclicSymmetryModel
+//
+// Examples:
+//
+//	*CYCLIC SYMMETRY MODEL,N=12,NGRAPH=1
+//	0.,0.,0.,1.,0.,0.
+//
+//	*CYCLIC SYMMETRY MODEL,N=12,TIE=CS1,ELSET=Es1
+//	0.,0.,0.,0.,0.,1.
+//
+// First line:
+//
+//	*CYCLIC SYMMETRY MODEL
+//	Enter the parameters N and TIE and their values. Optional
+//	parameters are NGRAPH (default 1), ELSET and CHECK (YES or NO).
+//
+// Second line:
+//
+//	X-coordinate of point a on cyclic symmetry axis.
+//	Y-coordinate of point a.
+//	Z-coordinate of point a.
+//	X-coordinate of point b on cyclic symmetry axis.
+//	Y-coordinate of point b.
+//	Z-coordinate of point b.
+type CyclicSymmetryModel struct {
+	N      int // number of sectors in 360 degrees
+	NGraph int // number of sectors for graphical output
+	Tie    string
+	Elset  string
+	Check  string // YES or NO
+	A, B   [3]float64
+}
+
+func (c CyclicSymmetryModel) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*CYCLIC SYMMETRY MODEL, N=%d", c.N)
+	if c.NGraph != 0 {
+		fmt.Fprintf(&buf, ", NGRAPH=%d", c.NGraph)
+	}
+	if c.Tie != "" {
+		fmt.Fprintf(&buf, ", TIE=%s", c.Tie)
+	}
+	if c.Elset != "" {
+		fmt.Fprintf(&buf, ", ELSET=%s", c.Elset)
+	}
+	if c.Check != "" {
+		fmt.Fprintf(&buf, ", CHECK=%s", c.Check)
+	}
+	fmt.Fprintf(&buf, "\n%s\n", pointsList(c.A, c.B))
+	return buf.String()
+}
+
+func (f *Model) parseCyclicSymmetryModel(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*CYCLIC SYMMETRY MODEL") {
+		return false, nil
+	}
+	var c CyclicSymmetryModel
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "N":
+			c.N, err = parseInt(value)
+		case "NGRAPH":
+			c.NGraph, err = parseInt(value)
+		case "TIE":
+			c.Tie = value
+		case "ELSET":
+			c.Elset = value
+		case "CHECK":
+			c.Check = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid cyclic symmetry model parameter: %s", part)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("cyclic symmetry model must have one line with axis")
+		return
+	}
+	vs, err := parseFloats(block[1])
+	if err != nil {
+		return
+	}
+	vs = append(vs, 0, 0, 0, 0, 0, 0)
+	copy(c.A[:], vs[:3])
+	copy(c.B[:], vs[3:6])
+	f.CyclicSymmetryModels = append(f.CyclicSymmetryModels, c)
+	return true, nil
+}
+
+// CyclicSymmetryModes
+//
+// Example:
+//
+//	*SELECT CYCLIC SYMMETRY MODES,NMIN=1,NMAX=1
+//
+// First and only line:
+//
+//	*SELECT CYCLIC SYMMETRY MODES
+//	Enter the parameters NMIN (default 0) and NMAX (default N/2).
+type CyclicSymmetryModes struct {
+	NMin   int
+	NMax   int
+	IsNMax bool // zero is valid value of NMAX
+}
+
+func (c CyclicSymmetryModes) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*SELECT CYCLIC SYMMETRY MODES, NMIN=%d", c.NMin)
+	if c.IsNMax || c.NMax != 0 {
+		fmt.Fprintf(&buf, ", NMAX=%d", c.NMax)
+	}
+	fmt.Fprintf(&buf, "\n")
+	return buf.String()
+}
+
+func (s *Step) parseCyclicSymmetryModes(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SELECT CYCLIC SYMMETRY MODES") {
+		return false, nil
+	}
+	var c CyclicSymmetryModes
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NMIN":
+			c.NMin, err = parseInt(value)
+		case "NMAX":
+			c.NMax, err = parseInt(value)
+			c.IsNMax = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid select cyclic symmetry modes parameter: %s", part)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if len(block) != 1 {
+		err = fmt.Errorf("select cyclic symmetry modes have not data lines")
+		return
+	}
+	s.CyclicSymmetryModes = append(s.CyclicSymmetryModes, c)
+	return true, nil
+}
+
+// rotateAroundAxis rotate point p around axis from point a with
+// unit direction u by angle in radians.
+func rotateAroundAxis(p, a, u [3]float64, angle float64) (r [3]float64) {
+	v := sub(p, a)
+	c, s := math.Cos(angle), math.Sin(angle)
+	k := cross(u, v)
+	d := dot(u, v)
+	for i := range r {
+		r[i] = a[i] + v[i]*c + k[i]*s + u[i]*d*(1-c)
+	}
+	return
+}
+
+// deepCopy return copy of value with copied slices, maps and pointers,
+// so that changes of copy are not influent on value. Unexported fields
+// are not copied deep.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(it.Key(), deepCopy(it.Value()))
+		}
+		return c
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	}
+	return v
+}
+
+// ExpandCyclic return model with nodes, elements and sets copied around
+// axis of the first cyclic symmetry model. Amount of sectors is n,
+// if n is not positive, then the full 360 degrees model is created.
+// Node and element numbers of sector k are shifted by k multiplied to
+// maximal node and element number of the base sector. Other data of
+// model is copied, so that changes of expanded model are not influent
+// on model f.
+func (f Model) ExpandCyclic(n int) (expanded Model, err error) {
+	if len(f.CyclicSymmetryModels) == 0 {
+		err = fmt.Errorf("cyclic symmetry model is not defined")
+		return
+	}
+	c := f.CyclicSymmetryModels[0]
+	if c.N <= 0 {
+		err = fmt.Errorf("not valid amount of sectors: %d", c.N)
+		return
+	}
+	if n <= 0 {
+		n = c.N
+	}
+	axis := sub(c.B, c.A)
+	length := norm(axis)
+	if length == 0 {
+		err = fmt.Errorf("cyclic symmetry axis is not defined")
+		return
+	}
+	for i := range axis {
+		axis[i] /= length
+	}
+	var nodeShift, elementShift int
+	for _, node := range f.Nodes {
+		if nodeShift < node.Index {
+			nodeShift = node.Index
+		}
+	}
+	for _, el := range f.Elements {
+		if elementShift < el.Index {
+			elementShift = el.Index
+		}
+	}
+
+	base := f
+	base.Nodes = nil
+	base.Elements = nil
+	expanded = deepCopy(reflect.ValueOf(base)).Interface().(Model)
+	for k := 0; k < n; k++ {
+		angle := 2 * math.Pi * float64(k) / float64(c.N)
+		for _, node := range f.Nodes {
+			node.Index += k * nodeShift
+			node.Coord = rotateAroundAxis(node.Coord, c.A, axis, angle)
+			expanded.Nodes = append(expanded.Nodes, node)
+		}
+		for _, el := range f.Elements {
+			nodes := make([]int, len(el.Nodes))
+			for i := range el.Nodes {
+				nodes[i] = el.Nodes[i] + k*nodeShift
+			}
+			el.Index += k * elementShift
+			el.Nodes = nodes
+			expanded.Elements = append(expanded.Elements, el)
+		}
+	}
+	expandSets := func(sets []Set, shift int) (es []Set) {
+		for _, s := range sets {
+			list := s.List()
+			s.Generate = false
+			s.Indexes = nil
+			for k := 0; k < n; k++ {
+				for _, index := range list {
+					s.Indexes = append(s.Indexes, index+k*shift)
+				}
+			}
+			es = append(es, s)
+		}
+		return
+	}
+	expanded.Nsets = expandSets(expanded.Nsets, nodeShift)
+	expanded.Elsets = expandSets(expanded.Elsets, elementShift)
+	return
+}
@@ -0,0 +1,481 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// FluidSection
+//
+// Examples:
+//
+//	*FLUID SECTION,ELSET=Ewater,MATERIAL=WATER,TYPE=PIPE MANNING
+//	1.E-2,2.5E-2,0.015
+//
+//	*FLUID SECTION,ELSET=E1,MATERIAL=GAS,TYPE=ORIFICE
+//	3.14E-6,0.002,0.004,0.,0.
+//
+// First line:
+//
+//	*FLUID SECTION
+//	Enter the parameters ELSET and MATERIAL and their values, and, if
+//	necessary, the parameters TYPE, OIL, LIQUID, MANNING, CONSTANTS.
+//
+// Following line (maximum 8 entries per line):
+//
+//	Constants of fluid section type.
+type FluidSection struct {
+	Elset     string
+	Material  string
+	Type      string // for example: ORIFICE, PIPE MANNING, VORTEX FREE
+	Oil       string
+	Liquid    bool
+	Manning   bool
+	Constants []float64
+}
+
+// fluidSectionConstants is names of constants for fluid section types.
+// Key is type without spaces.
+var fluidSectionConstants = map[string][]string{
+	"ORIFICE":             {"A", "d", "L", "r", "theta"},
+	"PIPEMANNING":         {"A", "R", "n"},
+	"PIPEMANNINGFLEXIBLE": {"A", "R", "n"},
+	"PIPEWHITE-COLEBROOK": {"A", "D", "L", "ks", "form factor"},
+	"PIPEENLARGEMENT":     {"A1", "A2"},
+	"PIPECONTRACTION":     {"A1", "A2"},
+	"PIPEENTRANCE":        {"A", "A0"},
+	"PIPEDIAPHRAGM":       {"A", "A0"},
+	"PIPEBEND":            {"A", "R/D", "alpha", "zeta"},
+	"PIPEGATEVALVE":       {"A", "alpha"},
+	"VORTEXFREE":          {"R2", "R1", "eta"},
+	"VORTEXFORCED":        {"R2", "R1", "eta"},
+	"INOUT":               {},
+	"PIPEINOUT":           {},
+
+	// gas pipes
+	"GASPIPEFANNOADIABATIC":  {"A", "D", "L", "ks", "form factor"},
+	"GASPIPEFANNOISOTHERMAL": {"A", "D", "L", "ks", "form factor"},
+	"ROTATINGGASPIPE":        {"A1", "A2", "L", "ks", "form factor", "D1", "D2", "R1", "R2", "n"},
+
+	// restrictors, upstream and downstream areas and hydraulic diameter
+	"RESTRICTORUSER":                    {"A1", "A2", "Dh", "zeta"},
+	"RESTRICTORENTRANCE":                {"A1", "A2", "Dh"},
+	"RESTRICTOREXIT":                    {"A1", "A2", "Dh"},
+	"RESTRICTORENLARGEMENT":             {"A1", "A2", "Dh"},
+	"RESTRICTORCONTRACTION":             {"A1", "A2", "Dh", "L", "alpha"},
+	"RESTRICTORBENDIDELCIRC":            {"A1", "A2", "Dh", "R", "alpha"},
+	"RESTRICTORBENDIDELRECT":            {"A1", "A2", "Dh", "R", "alpha", "a", "b"},
+	"RESTRICTORBENDMILLER":              {"A1", "A2", "Dh", "R", "alpha"},
+	"RESTRICTORBENDOWN":                 {"A1", "A2", "Dh", "R", "alpha", "kb"},
+	"RESTRICTORWALLORIFICE":             {"A1", "A2", "Dh", "L"},
+	"RESTRICTORLONGORIFICEIDELCHIK":     {"A1", "A2", "Dh", "L"},
+	"RESTRICTORLONGORIFICELICHTAROWICZ": {"A1", "A2", "Dh", "L"},
+
+	// labyrinths
+	"LABYRINTHSINGLE":           {"t", "s", "iexp", "d", "n", "b", "h", "L", "r", "X", "Hst"},
+	"LABYRINTHSTRAIGHT":         {"t", "s", "iexp", "d", "n", "b", "h", "L", "r", "X", "Hst"},
+	"LABYRINTHSTEPPED":          {"t", "s", "iexp", "d", "n", "b", "h", "L", "r", "X", "Hst"},
+	"LABYRINTHFLEXIBLESINGLE":   {"t", "s", "iexp", "d", "n", "b", "h", "L", "r", "X", "Hst"},
+	"LABYRINTHFLEXIBLESTRAIGHT": {"t", "s", "iexp", "d", "n", "b", "h", "L", "r", "X", "Hst"},
+	"LABYRINTHFLEXIBLESTEPPED":  {"t", "s", "iexp", "d", "n", "b", "h", "L", "r", "X", "Hst"},
+
+	// rotating disks
+	"MOEHRINGCENTRIPETAL": {"Rmin", "Rmax"},
+	"MOEHRINGCENTRIFUGAL": {"Rmin", "Rmax"},
+
+	// branches, element labels of branches, areas and angles
+	"BRANCHJOINTGE":        {"label0", "label1", "label2", "A0", "A1", "A2", "alpha1", "alpha2"},
+	"BRANCHJOINTIDELCHIK1": {"label0", "label1", "label2", "A0", "A1", "A2", "alpha1", "alpha2"},
+	"BRANCHJOINTIDELCHIK2": {"label0", "label1", "label2", "A0", "A1", "A2", "alpha1", "alpha2"},
+	"BRANCHSPLITGE":        {"label0", "label1", "label2", "A0", "A1", "A2", "alpha1", "alpha2"},
+	"BRANCHSPLITIDELCHIK1": {"label0", "label1", "label2", "A0", "A1", "A2", "alpha1", "alpha2"},
+	"BRANCHSPLITIDELCHIK2": {"label0", "label1", "label2", "A0", "A1", "A2", "alpha1", "alpha2"},
+
+	// types with constants without names, for example curves
+	"CARBONSEAL":         {"D", "s", "L"},
+	"CHARACTERISTIC":     {},
+	"LIQUIDPUMP":         {},
+	"PRESWIRLNOZZLE":     {},
+	"ABSOLUTETORELATIVE": {},
+	"RELATIVETOABSOLUTE": {},
+	"MASSFLOWPERCENT":    {},
+	"INLET":              {},
+	"OUTLET":             {},
+
+	// open channels, n/ks is Manning or White-Colebrook coefficient
+	"CHANNELINOUT":                {},
+	"CHANNELRESERVOIR":            {"b", "S0", "dl", "theta", "n/ks", "label"},
+	"CHANNELSTRAIGHT":             {"b", "S0", "dl", "theta", "n/ks", "label"},
+	"CHANNELSLUICEOPENING":        {"b", "S0", "dl", "theta", "n/ks", "label"},
+	"CHANNELWEIRSLOPE":            {"b", "S0", "dl", "theta", "n/ks", "label"},
+	"CHANNELDISCONTINUOUSSLOPE":   {"b", "S0", "dl", "theta", "n/ks", "label"},
+	"CHANNELDISCONTINUOUSOPENING": {"b", "S0", "dl", "theta", "n/ks", "label"},
+	"CHANNELSLUICEGATE":           {"b", "S0", "w", "", "label"},
+	"CHANNELWEIRCREST":            {"b", "p", "c", "", "label"},
+	"CHANNELCONTRACTION":          {"b1", "S0", "dl", "b2", "theta", "label"},
+	"CHANNELENLARGEMENT":          {"b1", "S0", "dl", "b2", "theta", "label"},
+	"CHANNELDROP":                 {"b", "S0", "dl", "hd", "theta", "label"},
+	"CHANNELSTEP":                 {"b", "S0", "dl", "hd", "theta", "label"},
+}
+
+// NamedConstants return constants of fluid section with names of
+// CalculiX documentation. Constants without names, for example oil
+// constants after constants of type, are not returned. Error is
+// returned for unknown type.
+func (fs FluidSection) NamedConstants() (named map[string]float64, err error) {
+	typ := strings.ReplaceAll(fs.Type, " ", "")
+	names, ok := fluidSectionConstants[typ]
+	if typ == "" || strings.HasPrefix(typ, "U") {
+		// section without type or user-defined type
+		ok = true
+	}
+	if !ok {
+		err = fmt.Errorf("fluid section type %s is not supported", fs.Type)
+		return
+	}
+	named = map[string]float64{}
+	for i, name := range names {
+		if name != "" && i < len(fs.Constants) {
+			named[name] = fs.Constants[i]
+		}
+	}
+	return
+}
+
+func (fs FluidSection) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*FLUID SECTION, ELSET=%s", fs.Elset)
+	if fs.Material != "" {
+		fmt.Fprintf(&buf, ", MATERIAL=%s", fs.Material)
+	}
+	if fs.Type != "" {
+		fmt.Fprintf(&buf, ", TYPE=%s", fs.Type)
+	}
+	if fs.Oil != "" {
+		fmt.Fprintf(&buf, ", OIL=%s", fs.Oil)
+	}
+	if fs.Liquid {
+		fmt.Fprintf(&buf, ", LIQUID")
+	}
+	if fs.Manning {
+		fmt.Fprintf(&buf, ", MANNING")
+	}
+	fmt.Fprintf(&buf, "\n")
+	for i, c := range fs.Constants {
+		fmt.Fprintf(&buf, "%s", efmt.Sprint(c))
+		if i == len(fs.Constants)-1 || (i+1)%8 == 0 {
+			fmt.Fprintf(&buf, "\n")
+		} else {
+			fmt.Fprintf(&buf, ", ")
+		}
+	}
+	return buf.String()
+}
+
+func (f *Model) parseFluidSection(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*FLUID SECTION") {
+		return false, nil
+	}
+	var fs FluidSection
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ELSET":
+			fs.Elset = value
+		case "MATERIAL":
+			fs.Material = value
+		case "TYPE":
+			fs.Type = strings.ToUpper(value)
+		case "OIL":
+			fs.Oil = value
+		case "LIQUID":
+			fs.Liquid = true
+		case "MANNING":
+			fs.Manning = true
+		case "CONSTANTS":
+			// amount of constants, calculated from data lines
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid fluid section parameter: %s", part)
+			return
+		}
+	}
+	for _, line := range block[1:] {
+		for _, field := range fields(line) {
+			if field == "" {
+				continue
+			}
+			var v float64
+			v, err = parseFloat(field)
+			if err != nil {
+				return
+			}
+			fs.Constants = append(fs.Constants, v)
+		}
+	}
+	f.FluidSections = append(f.FluidSections, fs)
+	return true, nil
+}
+
+// FluidConstant is line of *FLUID CONSTANTS
+//
+// Example:
+//
+//	*FLUID CONSTANTS
+//	1.0022E3,1.69532E-05,270
+//	1.0045E3,1.84135E-05,300
+//
+// Following line:
+//
+//	Specific heat at constant pressure.
+//	Dynamic viscosity.
+//	Temperature.
+type FluidConstant struct {
+	SpecificHeat float64
+	Viscosity    float64
+	Temperature  float64
+}
+
+func (f *Model) parseFluidConstants(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*FLUID CONSTANTS") {
+		return false, nil
+	}
+	m := f.lastMaterial()
+	for _, line := range block[1:] {
+		var vs []float64
+		vs, err = parseFloats(line)
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0, 0)
+		m.FluidConstants = append(m.FluidConstants, FluidConstant{
+			SpecificHeat: vs[0],
+			Viscosity:    vs[1],
+			Temperature:  vs[2],
+		})
+	}
+	return true, nil
+}
+
+// SpecificGasConstant
+//
+// Example:
+//
+//	*SPECIFIC GAS CONSTANT
+//	287.
+func (f *Model) parseSpecificGasConstant(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SPECIFIC GAS CONSTANT") {
+		return false, nil
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("specific gas constant must have one data line")
+		return
+	}
+	f.lastMaterial().SpecificGasConstant, err = parseFloat(fields(block[1])[0])
+	return err == nil, err
+}
+
+// BoundaryF is boundary conditions for CFD calculations on faces.
+//
+// Example:
+//
+//	*BOUNDARYF
+//	Sin,S,2,3,0.
+//	91, S4, 2,, 0.000000
+//
+// First line:
+//
+//	*BOUNDARYF
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, USER.
+//	Block *BOUNDARYF,OP=NEW without following lines removes all
+//	CFD boundaries.
+//
+// Following line:
+//
+//	Element number or facial surface label.
+//	Face label (S1-S6 for element, S for surface).
+//	First degree of freedom constrained.
+//	Last degree of freedom constrained.
+//	Actual magnitude of the prescribed value.
+type BoundaryF struct {
+	Element string
+	Face    string
+	Start   int
+	Finish  int
+	Factor  float64
+
+	LoadOptions
+
+	block int // sequence number of source block
+}
+
+func (b BoundaryF) line() string {
+	var finish string
+	if b.Finish != 0 {
+		finish = fmt.Sprintf("%d", b.Finish)
+	}
+	return fmt.Sprintf("%s, %s, %d, %s, %s",
+		b.Element, b.Face, b.Start, finish, efmt.Sprint(b.Factor))
+}
+
+func (s *Step) parseBoundaryF(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*BOUNDARYF") {
+		return false, nil
+	}
+	var o LoadOptions
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = o.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		err = fmt.Errorf("not valid boundaryf parameter: %s", part)
+		return
+	}
+	var number int
+	if n := len(s.BoundaryFs); 0 < n {
+		number = s.BoundaryFs[n-1].block + 1
+	}
+	if len(block) == 1 {
+		if o.Op != "NEW" {
+			return false, fmt.Errorf("not valid boundaryf")
+		}
+		// block without rows removes all CFD boundaries
+		s.BoundaryFs = append(s.BoundaryFs, BoundaryF{LoadOptions: o, block: number})
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 3 {
+			err = fmt.Errorf("not valid boundaryf line: %s", line)
+			return
+		}
+		b := BoundaryF{Element: fs[0], Face: strings.ToUpper(fs[1]), LoadOptions: o, block: number}
+		b.Start, err = parseInt(fs[2])
+		if err != nil {
+			return
+		}
+		fs = append(fs, "", "")
+		if fs[3] != "" {
+			b.Finish, err = parseInt(fs[3])
+			if err != nil {
+				return
+			}
+		}
+		if fs[4] != "" {
+			b.Factor, err = parseFloat(fs[4])
+			if err != nil {
+				return
+			}
+		}
+		s.BoundaryFs = append(s.BoundaryFs, b)
+	}
+	return true, nil
+}
+
+// MassFlow is mass flow through faces for CFD calculations.
+//
+// Example:
+//
+//	*MASS FLOW
+//	Sside,M,0.
+//
+// First line:
+//
+//	*MASS FLOW
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY.
+//	Block *MASS FLOW,OP=NEW without following lines removes all
+//	mass flows.
+//
+// Following line:
+//
+//	Element number or facial surface label.
+//	Mass flow face label (M1-M6 for element, M for surface).
+//	Actual magnitude of the mass flow.
+type MassFlow struct {
+	Element string
+	Label   string
+	Value   float64
+
+	LoadOptions
+
+	block int // sequence number of source block
+}
+
+func (m MassFlow) line() string {
+	return fmt.Sprintf("%s, %s, %s", m.Element, m.Label, efmt.Sprint(m.Value))
+}
+
+func (s *Step) parseMassFlow(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*MASS FLOW") {
+		return false, nil
+	}
+	var o LoadOptions
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = o.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		err = fmt.Errorf("not valid mass flow parameter: %s", part)
+		return
+	}
+	var number int
+	if n := len(s.MassFlows); 0 < n {
+		number = s.MassFlows[n-1].block + 1
+	}
+	if len(block) == 1 {
+		if o.Op != "NEW" {
+			return false, fmt.Errorf("not valid mass flow")
+		}
+		// block without rows removes all mass flows
+		s.MassFlows = append(s.MassFlows, MassFlow{LoadOptions: o, block: number})
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid mass flow line: %s", line)
+			return
+		}
+		m := MassFlow{Element: fs[0], Label: strings.ToUpper(fs[1]), LoadOptions: o, block: number}
+		if 2 < len(fs) && fs[2] != "" {
+			m.Value, err = parseFloat(fs[2])
+			if err != nil {
+				return
+			}
+		}
+		s.MassFlows = append(s.MassFlows, m)
+	}
+	return true, nil
+}
+
+// writeFluidBoundaries write CFD boundaries and mass flows. Rows with
+// same parameters and of the same block are written in one block. Row
+// without element is written as block without rows.
+func writeFluidBoundaries(buf *bytes.Buffer, bs []BoundaryF, ms []MassFlow) {
+	for i, b := range bs {
+		if i == 0 || b.LoadOptions != bs[i-1].LoadOptions ||
+			b.block != bs[i-1].block || bs[i-1].Element == "" {
+			fmt.Fprintf(buf, "*BOUNDARYF%s\n", b.LoadOptions)
+		}
+		if b.Element != "" {
+			fmt.Fprintf(buf, "%s\n", b.line())
+		}
+	}
+	for i, m := range ms {
+		if i == 0 || m.LoadOptions != ms[i-1].LoadOptions ||
+			m.block != ms[i-1].block || ms[i-1].Element == "" {
+			fmt.Fprintf(buf, "*MASS FLOW%s\n", m.LoadOptions)
+		}
+		if m.Element != "" {
+			fmt.Fprintf(buf, "%s\n", m.line())
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package inp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// faces of solid elements by CalculiX node numbering.
+// Corner nodes are first, middle nodes are last.
+var (
+	facesHexa = map[string][]int{
+		"S1": {1, 2, 3, 4, 9, 10, 11, 12},
+		"S2": {5, 8, 7, 6, 16, 15, 14, 13},
+		"S3": {1, 5, 6, 2, 17, 13, 18, 9},
+		"S4": {2, 6, 7, 3, 18, 14, 19, 10},
+		"S5": {3, 7, 8, 4, 19, 15, 20, 11},
+		"S6": {4, 8, 5, 1, 20, 16, 17, 12},
+	}
+	facesTetra = map[string][]int{
+		"S1": {1, 2, 3, 5, 6, 7},
+		"S2": {1, 4, 2, 8, 9, 5},
+		"S3": {2, 4, 3, 9, 10, 6},
+		"S4": {3, 4, 1, 10, 8, 7},
+	}
+	facesWedge = map[string][]int{
+		"S1": {1, 2, 3, 7, 8, 9},
+		"S2": {4, 5, 6, 10, 11, 12},
+		"S3": {1, 2, 5, 4, 7, 14, 10, 13},
+		"S4": {2, 3, 6, 5, 8, 15, 11, 14},
+		"S5": {3, 1, 4, 6, 9, 13, 12, 15},
+	}
+)
+
+// cornerNodes return amount of corner nodes of element type
+func cornerNodes(elType string) int {
+	switch {
+	case strings.HasPrefix(elType, "B"), strings.HasPrefix(elType, "T2D"),
+		strings.HasPrefix(elType, "T3D"):
+		return 2
+	case strings.HasPrefix(elType, "C3D8"), strings.HasPrefix(elType, "C3D20"):
+		return 8
+	case strings.HasPrefix(elType, "C3D6"), strings.HasPrefix(elType, "C3D15"):
+		return 6
+	case strings.HasPrefix(elType, "C3D4"), strings.HasPrefix(elType, "C3D10"):
+		return 4
+	case strings.HasSuffix(elType, "3"), strings.HasSuffix(elType, "3R"),
+		strings.HasSuffix(elType, "6"):
+		// S3, S6, CPS3, CPE6, CAX6, M3D3, ...
+		return 3
+	case strings.HasSuffix(elType, "4"), strings.HasSuffix(elType, "4R"),
+		strings.HasSuffix(elType, "8"), strings.HasSuffix(elType, "8R"):
+		// S4, S8R, CPS4, CAX8R, ...
+		return 4
+	}
+	return 2
+}
+
+// faceNodes return nodes of element face.
+// First `corners` nodes of face are corner nodes.
+func faceNodes(el Element, face string) (nodes []int, corners int, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("element %d type %s face %s: %v", el.Index, el.Type, face, err)
+		}
+	}()
+	var table map[string][]int
+	switch cornerNodes(el.Type) {
+	case 8:
+		table = facesHexa
+	case 6:
+		table = facesWedge
+	case 4:
+		if strings.HasPrefix(el.Type, "C3D") {
+			table = facesTetra
+		}
+	}
+	if table != nil {
+		ns, ok := table[face]
+		if !ok {
+			err = fmt.Errorf("not valid face")
+			return
+		}
+		corners = 3
+		if 6 < len(ns) {
+			corners = 4
+		}
+		for _, n := range ns {
+			if n <= len(el.Nodes) {
+				nodes = append(nodes, el.Nodes[n-1])
+			}
+		}
+		return
+	}
+	// shell, membrane and plane elements
+	corners = cornerNodes(el.Type)
+	if len(el.Nodes) < corners {
+		err = fmt.Errorf("not enough nodes")
+		return
+	}
+	switch face {
+	case "SPOS", "SNEG", "S1", "S2":
+		if strings.HasPrefix(el.Type, "S") || strings.HasPrefix(el.Type, "M3D") {
+			return el.Nodes, corners, nil
+		}
+	}
+	// edge of plane element
+	var edge int
+	if _, err = fmt.Sscanf(face, "S%d", &edge); err != nil || edge < 1 || corners < edge {
+		err = fmt.Errorf("not valid face")
+		return
+	}
+	nodes = []int{el.Nodes[edge-1], el.Nodes[edge%corners]}
+	if mid := corners + edge - 1; mid < len(el.Nodes) {
+		nodes = append(nodes, el.Nodes[mid])
+	}
+	return nodes, 2, nil
+}
+
+func sub(a, b [3]float64) [3]float64 {
+	return [3]float64{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func norm(a [3]float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+func distance(a, b [3]float64) float64 {
+	return norm(sub(a, b))
+}
+
+// pointSegmentDistance return distance between point p and segment a-b
+func pointSegmentDistance(p, a, b [3]float64) float64 {
+	ab := sub(b, a)
+	l := dot(ab, ab)
+	if l == 0 {
+		return distance(p, a)
+	}
+	t := math.Max(0, math.Min(1, dot(sub(p, a), ab)/l))
+	return distance(p, [3]float64{a[0] + t*ab[0], a[1] + t*ab[1], a[2] + t*ab[2]})
+}
+
+// pointTriangleDistance return distance between point p and triangle a-b-c
+func pointTriangleDistance(p, a, b, c [3]float64) float64 {
+	n := cross(sub(b, a), sub(c, a))
+	if l := norm(n); 0 < l {
+		// projection of point inside triangle
+		h := dot(sub(p, a), n) / l
+		inside := true
+		for _, e := range [3][2][3]float64{{a, b}, {b, c}, {c, a}} {
+			if dot(cross(sub(e[1], e[0]), sub(p, e[0])), n) < 0 {
+				inside = false
+			}
+		}
+		if inside {
+			return math.Abs(h)
+		}
+	}
+	return math.Min(pointSegmentDistance(p, a, b),
+		math.Min(pointSegmentDistance(p, b, c), pointSegmentDistance(p, c, a)))
+}
+
+// pointFaceDistance return distance between point and face with
+// corner points. Quadrilateral face is splitted into 2 triangles.
+func pointFaceDistance(p [3]float64, corners [][3]float64) float64 {
+	switch len(corners) {
+	case 0:
+		return math.Inf(1)
+	case 1:
+		return distance(p, corners[0])
+	case 2:
+		return pointSegmentDistance(p, corners[0], corners[1])
+	case 3:
+		return pointTriangleDistance(p, corners[0], corners[1], corners[2])
+	}
+	return math.Min(
+		pointTriangleDistance(p, corners[0], corners[1], corners[2]),
+		pointTriangleDistance(p, corners[0], corners[2], corners[3]),
+	)
+}
+
+// nodeCoordinates return map of node index to coordinate
+func (f Model) nodeCoordinates() map[int][3]float64 {
+	coords := make(map[int][3]float64, len(f.Nodes))
+	for _, n := range f.Nodes {
+		coords[n.Index] = n.Coord
+	}
+	return coords
+}
+
+// elementByIndex return map of element index to element
+func (f Model) elementByIndex() map[int]Element {
+	els := make(map[int]Element, len(f.Elements))
+	for _, el := range f.Elements {
+		els[el.Index] = el
+	}
+	return els
+}
+
+// surface return surface by name
+func (f Model) surface(name string) (s Surface, err error) {
+	for _, s := range f.Surfaces {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	err = fmt.Errorf("not found surface: %s", name)
+	return
+}
+
+// surfaceFace - face of element in element face based surface
+type surfaceFace struct {
+	Element int
+	Nodes   []int
+	Corners int
+}
+
+// surfaceFaces return all faces of element face based surface
+func (f Model) surfaceFaces(s Surface) (faces []surfaceFace, err error) {
+	if !s.IsElementType {
+		err = fmt.Errorf("surface %s is not based on element faces", s.Name)
+		return
+	}
+	els := f.elementByIndex()
+	for _, l := range s.List {
+		var indexes []int
+		indexes, err = f.elementLocation(l[0])
+		if err != nil {
+			return
+		}
+		for _, index := range indexes {
+			el, ok := els[index]
+			if !ok {
+				err = fmt.Errorf("not found element %d of surface %s", index, s.Name)
+				return
+			}
+			var face surfaceFace
+			face.Element = index
+			face.Nodes, face.Corners, err = faceNodes(el, l[1])
+			if err != nil {
+				return
+			}
+			faces = append(faces, face)
+		}
+	}
+	return
+}
+
+// surfaceNodes return unique nodes of surface
+func (f Model) surfaceNodes(s Surface) (nodes []int, err error) {
+	used := map[int]bool{}
+	add := func(list []int) {
+		for _, n := range list {
+			if !used[n] {
+				used[n] = true
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	if !s.IsElementType {
+		for _, l := range s.List {
+			var list []int
+			list, err = f.nodeLocation(l[0])
+			if err != nil {
+				return
+			}
+			add(list)
+		}
+		return
+	}
+	faces, err := f.surfaceFaces(s)
+	if err != nil {
+		return
+	}
+	for _, face := range faces {
+		add(face.Nodes)
+	}
+	return
+}
@@ -17,6 +17,7 @@ import (
 	"os"
 	"runtime"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -47,27 +48,46 @@ func CcxCpu(cpu int) {
 
 // Model - summary inp format
 type Model struct {
-	Heading           string
-	Nodes             []Node
-	Elements          []Element
-	Nsets             []Set
-	Elsets            []Set
-	Surfaces          []Surface
-	Materials         []Material
-	InitialConditions Condition
-	BeamSections      []BeamSection
-	SolidSections     []SolidSection
-	ShellSections     []ShellSection
-	Boundaries        []Boundary
-	Springs           []Spring
-	Steps             []Step
-	TimePoint         struct {
-		Name     string
-		Generate bool
-		Time     []float64
-	}
+	Heading               string
+	Nodes                 []Node
+	Elements              []Element
+	Nsets                 []Set
+	Elsets                []Set
+	Surfaces              []Surface
+	Materials             []Material
+	InitialConditions     Condition // Deprecated: use Conditions, read-only first conditions
+	Conditions            []Condition
+	BeamSections          []BeamSection
+	SolidSections         []SolidSection
+	ShellSections         []ShellSection
+	Boundaries            []Boundary
+	Springs               []Spring
+	Steps                 []Step
+	TimePoints            []TimePoints
 	RigidBodies           []RigidBody
 	DistributingCouplings []DistributingCoupling
+	ContactPairs          []ContactPair
+	SurfaceInteractions   []SurfaceInteraction
+	Gaps                  []Gap
+	Ties                  []Tie
+	Orientations          []Orientation
+	Transforms            []Transform
+	CyclicSymmetryModels  []CyclicSymmetryModel
+	FluidSections         []FluidSection
+	DesignVariables       DesignVariables
+	NodalThicknesses      []NodalThickness
+	Normals               []Normal
+	Masses                []Mass
+	Dashpots              []Dashpot
+	RotaryInertias        []RotaryInertia
+	Couplings             []Coupling
+	Submodels             []Submodel
+	PhysicalConstants     PhysicalConstants
+	Restart               Restart
+
+	// Source is keywords of source input file in source order with
+	// comments. Used by Model.WriteTo with option Source.
+	Source []Keyword
 }
 
 type Property struct {
@@ -82,11 +102,13 @@ type Expansion struct {
 }
 
 type Material struct {
-	Name       string
-	Density    float64
-	Expansions []Expansion
-	Properties []Property
-	Plastic    struct {
+	Name        string
+	Density     float64
+	Damping     Damping
+	Expansions  []Expansion
+	Properties  []Property
+	ElasticType string // ISO, ORTHO, ENGINEERING CONSTANTS or ANISO
+	Plastic     struct {
 		Hardening string
 		Data      [10]struct {
 			StressVonMises float64
@@ -94,6 +116,13 @@ type Material struct {
 			Temperature    float64
 		}
 	}
+
+	FluidConstants      []FluidConstant
+	SpecificGasConstant float64
+
+	ConductivityType string // ISO, ORTHO or ANISO
+	Conductivities   []Conductivity
+	SpecificHeats    []SpecificHeat
 }
 
 func (m Material) String() string {
@@ -102,7 +131,11 @@ func (m Material) String() string {
 		fmt.Fprintf(&buf, "*MATERIAL, NAME=%s\n", m.Name)
 	}
 	if 0 < len(m.Properties) {
-		fmt.Fprintf(&buf, "*ELASTIC\n")
+		fmt.Fprintf(&buf, "*ELASTIC")
+		if m.ElasticType != "" {
+			fmt.Fprintf(&buf, ", TYPE=%s", m.ElasticType)
+		}
+		fmt.Fprintf(&buf, "\n")
 		for _, pr := range m.Properties {
 			fmt.Fprintf(&buf, "%s, %s, %s\n",
 				efmt.Sprint(pr.E),
@@ -111,8 +144,16 @@ func (m Material) String() string {
 			)
 		}
 	}
-	if m.Plastic.Hardening != "" {
-		fmt.Fprintf(&buf, "*PLASTIC, HARDENING=%s\n", m.Plastic.Hardening)
+	plastic := m.Plastic.Hardening != ""
+	for _, d := range m.Plastic.Data {
+		plastic = plastic || d.StressVonMises != 0.0
+	}
+	if plastic {
+		fmt.Fprintf(&buf, "*PLASTIC")
+		if m.Plastic.Hardening != "" {
+			fmt.Fprintf(&buf, ", HARDENING=%s", m.Plastic.Hardening)
+		}
+		fmt.Fprintf(&buf, "\n")
 		for _, d := range m.Plastic.Data {
 			if d.StressVonMises != 0.0 {
 				fmt.Fprintf(&buf, "%s, %s, %s\n",
@@ -134,6 +175,17 @@ func (m Material) String() string {
 			)
 		}
 	}
+	m.writeThermalProperties(&buf)
+	fmt.Fprintf(&buf, "%s", m.Damping)
+	if 0 < len(m.FluidConstants) {
+		fmt.Fprintf(&buf, "*FLUID CONSTANTS\n")
+		for _, fc := range m.FluidConstants {
+			fmt.Fprintf(&buf, "%s\n", floatList(fc.SpecificHeat, fc.Viscosity, fc.Temperature))
+		}
+	}
+	if m.SpecificGasConstant != 0 {
+		fmt.Fprintf(&buf, "*SPECIFIC GAS CONSTANT\n%s\n", efmt.Sprint(m.SpecificGasConstant))
+	}
 	fmt.Fprintf(&buf, "*DENSITY\n%s,\n", efmt.Sprint(m.Density))
 	return buf.String()
 }
@@ -161,147 +213,356 @@ func (s Surface) String() string {
 		}
 	} else {
 		fmt.Fprintf(&buf, ", TYPE=NODE\n")
-		for i, l := range s.List {
-			fmt.Fprintf(&buf, "%s", l[0])
-			if i == len(s.List) {
-				fmt.Fprintf(&buf, "\n")
-			} else {
-				fmt.Fprintf(&buf, ",\n")
-			}
+		for _, l := range s.List {
+			fmt.Fprintf(&buf, "%s\n", l[0])
 		}
 	}
 	fmt.Fprintf(&buf, "\n")
 	return buf.String()
 }
 
+// parseSurface
+//
+// Examples:
+//
+//	*SURFACE, NAME=SMAST
+//	34, S2
+//
+//	*SURFACE, NAME=BALL, TYPE=NODE
+//	NSURFACE
+//
+// First line:
+//
+//	*SURFACE
+//	Enter the parameter NAME and its value, and, if necessary, the TYPE parameter.
+//
+// Following line for surfaces of type ELEMENT:
+//
+//	Element or element set label.
+//	Surface label.
+//
+// Following line for surfaces of type NODE:
+//
+//	Node or node set label.
+func (f *Model) parseSurface(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SURFACE") {
+		return false, nil
+	}
+	s := Surface{IsElementType: true}
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NAME":
+			s.Name = value
+		case "TYPE":
+			switch value {
+			case "ELEMENT":
+				s.IsElementType = true
+			case "NODE":
+				s.IsElementType = false
+			default:
+				err = fmt.Errorf("not valid surface type: %s", part)
+				return
+			}
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid surface parameter: %s", part)
+			return
+		}
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if s.IsElementType {
+			if len(fs) < 2 {
+				err = fmt.Errorf("not valid surface line: %s", line)
+				return
+			}
+			s.List = append(s.List, [2]string{fs[0], fs[1]})
+			continue
+		}
+		for _, f := range fs {
+			if f == "" {
+				continue
+			}
+			s.List = append(s.List, [2]string{f, ""})
+		}
+	}
+	f.Surfaces = append(f.Surfaces, s)
+	return true, nil
+}
+
 type Step struct {
 	IsStatic bool
-	Static   struct {
-		TimeInc    float64
-		TimePeriod float64
-	}
+	Static   Static
 
 	Nlgeom bool // genuine nonlinear geometric calculation
 	Inc    int  // The maximum number of increments in the step (for automatic
 	// incrementation) can be specified by using
 	// the parameter INC (default is 100)
 
+	IsHeatTransfer bool
+	HeatTransfer   HeatTransfer
+
+	IsSensitivity       bool
+	Sensitivity         Sensitivity
+	IsFeasibleDirection bool
+	FeasibleDirection   FeasibleDirection
+
 	Boundaries []Boundary
 
-	Buckle struct {
-		Number   int     // Number of buckling factors desired (usually 1)
-		Accuracy float64 // Accuracy desired (default: 0.01).
-	}
-	NodeFiles    []Print
-	ElFiles      []Print
-	NodePrints   []Print
-	ElPrints     []Print
-	Cloads       []Cload
-	Dloads       []Dload
-	Temperatures []Temperature
+	Buckle    Buckle
+	Frequency Frequency
+
+	IsDynamic             bool
+	Dynamic               Dynamic
+	IsModalDynamic        bool
+	ModalDynamic          ModalDynamic
+	IsSteadyStateDynamics bool
+	SteadyStateDynamics   SteadyStateDynamics
+
+	ModalDamping        ModalDamping
+	NodeFiles           []Print
+	ElFiles             []Print
+	NodePrints          []Print
+	ElPrints            []Print
+	ElementOutputs      []Print
+	ContactFiles        []Print
+	ContactPrints       []Print
+	SectionPrints       []SectionPrint
+	Cloads              []Cload
+	Dloads              []Dload
+	Dsloads             []Dsload
+	Temperatures        []Temperature
+	Dfluxes             []Dflux
+	Cfluxes             []Cflux
+	Films               []Film
+	Radiates            []Radiate
+	ModelChanges        []ModelChange
+	CyclicSymmetryModes []CyclicSymmetryModes
+	BoundaryFs          []BoundaryF
+	MassFlows           []MassFlow
+	Restart             Restart
+	Controls            []Controls
 }
 
 func (s Step) String() string {
-	var buf bytes.Buffer
-
-	fmt.Fprintf(&buf, "*STEP")
-	if s.Nlgeom {
-		fmt.Fprintf(&buf, ", NLGEOM")
-	} else {
-		fmt.Fprintf(&buf, ", NLGEOM=NO")
-	}
-	if s.Inc != 0 {
-		fmt.Fprintf(&buf, ", INC=%d", s.Inc)
-	}
-	fmt.Fprintf(&buf, "\n")
-
-	if s.IsStatic {
-		fmt.Fprintf(&buf, "*STATIC\n")
-		if s.Static.TimeInc != 0.0 || s.Static.TimePeriod != 0.0 {
-			fmt.Fprintf(&buf, "%.8e, %.8e\n",
-				s.Static.TimeInc, s.Static.TimePeriod)
-		}
-	}
+	return s.format(WriteOptions{})
+}
 
-	if 0 < s.Buckle.Number {
-		fmt.Fprintf(&buf, "*BUCKLE\n")
-		if s.Buckle.Accuracy == 0 {
-			fmt.Fprintf(&buf, "%d\n", s.Buckle.Number)
-		} else {
-			fmt.Fprintf(&buf, "%d,%.12e\n", s.Buckle.Number, s.Buckle.Accuracy)
-		}
+func (s Step) format(o WriteOptions) string {
+	var buf bytes.Buffer
+	for _, g := range s.itemGroups(o) {
+		g.write(&buf, 0, g.len())
 	}
+	return buf.String()
+}
 
-	for _, load := range s.Cloads {
-		fmt.Fprintf(&buf, "%s", load.String())
-	}
-	for _, load := range s.Dloads {
-		fmt.Fprintf(&buf, "%s", load.String())
-	}
-	for _, load := range s.Temperatures {
-		fmt.Fprintf(&buf, "%s", load.String())
-	}
-	for _, boun := range s.Boundaries {
-		if boun.LoadLocation == "" {
-			continue
-		}
-		fmt.Fprintf(&buf, "*BOUNDARY\n%s,%d,%d,%.8e\n",
-			boun.LoadLocation, boun.Start, boun.Finish, boun.Factor,
-		)
+// itemGroups return groups of step items in order of writing
+func (s *Step) itemGroups(o WriteOptions) []itemGroup {
+	prints := func(name, prefixName string, vs *[]Print) itemGroup {
+		return items(name, "", vs, func(w io.Writer, vs []Print) {
+			writePrints(w, "*"+name, prefixName, vs)
+		})
 	}
-
-	for _, slice := range []struct {
-		prefix     string
-		prefixName string
-		prints     []Print
-	}{
-		{prefix: "*NODE FILE", prefixName: "NSET", prints: s.NodeFiles},
-		{prefix: "*EL FILE", prefixName: "ELSET", prints: s.ElFiles},
-		{prefix: "*NODE PRINT", prefixName: "NSET", prints: s.NodePrints},
-		{prefix: "*EL PRINT", prefixName: "ELSET", prints: s.ElPrints},
-	} {
-		for _, pr := range slice.prints {
-			fmt.Fprintf(&buf, "%s", slice.prefix)
-			if pr.SetName != "" {
-				fmt.Fprintf(&buf, ", %s=%s", slice.prefixName, pr.SetName)
+	return []itemGroup{
+		{name: "STEP", write: func(w io.Writer, from, to int) {
+			fmt.Fprintf(w, "*STEP")
+			if s.Nlgeom {
+				fmt.Fprintf(w, ", NLGEOM")
+			} else {
+				fmt.Fprintf(w, ", NLGEOM=NO")
 			}
-			if pr.Frequency != "" {
-				fmt.Fprintf(&buf, ", FREQUENCY=%s", pr.Frequency)
+			if s.Inc != 0 {
+				fmt.Fprintf(w, ", INC=%d", s.Inc)
 			}
-			if pr.Output != "" {
-				fmt.Fprintf(&buf, ", OUTPUT=%s", pr.Output)
+			fmt.Fprintf(w, "\n")
+		}},
+		{name: "STATIC", write: func(w io.Writer, from, to int) {
+			if s.IsStatic {
+				fmt.Fprintf(w, "%s", s.Static)
 			}
-			if pr.Total != "" {
-				// for example:
-				// Calculix: ONLY
-				// Abaqus  : YES
-				fmt.Fprintf(&buf, ", TOTALS=%s", pr.Total)
+		}},
+		{name: "HEAT TRANSFER", write: func(w io.Writer, from, to int) {
+			if s.IsHeatTransfer {
+				fmt.Fprintf(w, "%s", s.HeatTransfer)
 			}
-			if pr.TimePoints != "" {
-				fmt.Fprintf(&buf, ", TIME POINTS=%s", pr.TimePoints)
+		}},
+		{name: "SENSITIVITY", write: func(w io.Writer, from, to int) {
+			if s.IsSensitivity {
+				fmt.Fprintf(w, "%s", s.Sensitivity)
 			}
-			if pr.ContactElement {
-				fmt.Fprintf(&buf, ", CONTACT ELEMENT")
+		}},
+		{name: "FEASIBLE DIRECTION", write: func(w io.Writer, from, to int) {
+			if s.IsFeasibleDirection {
+				fmt.Fprintf(w, "%s", s.FeasibleDirection)
 			}
-			if pr.Global {
-				fmt.Fprintf(&buf, ", GLOBAL=YES")
+		}},
+		items("OBJECTIVE", "", &s.Sensitivity.Objectives, writeObjectives),
+		items("CONSTRAINT", "", &s.Sensitivity.Constraints, writeConstraints),
+		{name: "FILTER", write: func(w io.Writer, from, to int) {
+			if s.Sensitivity.Filter != nil {
+				fmt.Fprintf(w, "%s", s.Sensitivity.Filter)
 			}
-			fmt.Fprintf(&buf, "\n%s\n", strings.Join(pr.Options, ", "))
-		}
+		}},
+		single("BUCKLE", "", &s.Buckle),
+		single("FREQUENCY", "", &s.Frequency),
+		single("DYNAMIC", "", &s.Dynamic),
+		single("MODAL DYNAMIC", "", &s.ModalDynamic),
+		single("STEADY STATE DYNAMICS", "", &s.SteadyStateDynamics),
+		single("MODAL DAMPING", "", &s.ModalDamping),
+		items("MODEL CHANGE", "", &s.ModelChanges, stringers[ModelChange]),
+		items("CYCLIC SYMMETRY MODES", "", &s.CyclicSymmetryModes, stringers[CyclicSymmetryModes]),
+		items("CONTROLS", "", &s.Controls, stringers[Controls]),
+		single("RESTART", "", &s.Restart),
+		items("CLOAD", "", &s.Cloads, func(w io.Writer, vs []Cload) {
+			var buf bytes.Buffer
+			writeCloads(&buf, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("DLOAD", "", &s.Dloads, func(w io.Writer, vs []Dload) {
+			var buf bytes.Buffer
+			writeDloads(&buf, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("DSLOAD", "", &s.Dsloads, func(w io.Writer, vs []Dsload) {
+			var buf bytes.Buffer
+			writeDsloads(&buf, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("TEMPERATURE", "", &s.Temperatures, func(w io.Writer, vs []Temperature) {
+			for _, load := range vs {
+				fmt.Fprintf(w, "%s", load.format(o))
+			}
+		}),
+		items("DFLUX", "", &s.Dfluxes, stringers[Dflux]),
+		items("CFLUX", "", &s.Cfluxes, stringers[Cflux]),
+		items("FILM", "", &s.Films, stringers[Film]),
+		items("RADIATE", "", &s.Radiates, stringers[Radiate]),
+		items("BOUNDARY", "", &s.Boundaries, func(w io.Writer, vs []Boundary) {
+			var buf bytes.Buffer
+			writeBoundaries(&buf, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("BOUNDARYF", "", &s.BoundaryFs, func(w io.Writer, vs []BoundaryF) {
+			var buf bytes.Buffer
+			writeFluidBoundaries(&buf, vs, nil)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("MASS FLOW", "", &s.MassFlows, func(w io.Writer, vs []MassFlow) {
+			var buf bytes.Buffer
+			writeFluidBoundaries(&buf, nil, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		prints("NODE FILE", "NSET", &s.NodeFiles),
+		prints("EL FILE", "ELSET", &s.ElFiles),
+		prints("ELEMENT OUTPUT", "ELSET", &s.ElementOutputs),
+		prints("CONTACT FILE", "NSET", &s.ContactFiles),
+		prints("NODE PRINT", "NSET", &s.NodePrints),
+		prints("EL PRINT", "ELSET", &s.ElPrints),
+		prints("CONTACT PRINT", "NSET", &s.ContactPrints),
+		items("SECTION PRINT", "", &s.SectionPrints, stringers[SectionPrint]),
+		{name: "END STEP", write: func(w io.Writer, from, to int) {
+			fmt.Fprintf(w, "*END STEP\n")
+		}},
 	}
+}
 
-	fmt.Fprintf(&buf, "*END STEP\n")
-
-	return buf.String()
+// writePrints write output requests with keyword prefix, for example
+// *NODE PRINT. Set name is written as parameter prefixName.
+func writePrints(w io.Writer, prefix, prefixName string, prints []Print) {
+	for _, pr := range prints {
+		fmt.Fprintf(w, "%s", prefix)
+		if pr.SetName != "" {
+			fmt.Fprintf(w, ", %s=%s", prefixName, pr.SetName)
+		}
+		if pr.Slave != "" {
+			fmt.Fprintf(w, ", SLAVE=%s", pr.Slave)
+		}
+		if pr.Master != "" {
+			fmt.Fprintf(w, ", MASTER=%s", pr.Master)
+		}
+		if pr.IsFrequency || pr.Frequency != 0 {
+			fmt.Fprintf(w, ", FREQUENCY=%d", pr.Frequency)
+		}
+		if pr.IsFrequencyF || pr.FrequencyF != 0 {
+			fmt.Fprintf(w, ", FREQUENCYF=%d", pr.FrequencyF)
+		}
+		if pr.Output != "" {
+			fmt.Fprintf(w, ", OUTPUT=%s", pr.Output)
+		}
+		if pr.Total != "" {
+			// for example:
+			// Calculix: ONLY
+			// Abaqus  : YES
+			fmt.Fprintf(w, ", TOTALS=%s", pr.Total)
+		}
+		if pr.TimePoints != "" {
+			fmt.Fprintf(w, ", TIME POINTS=%s", pr.TimePoints)
+		}
+		if pr.Position != "" {
+			fmt.Fprintf(w, ", POSITION=%s", pr.Position)
+		}
+		if pr.ContactElement {
+			fmt.Fprintf(w, ", CONTACT ELEMENT")
+		}
+		if pr.SectionForces {
+			fmt.Fprintf(w, ", SECTION FORCES")
+		}
+		if pr.LastIterations {
+			fmt.Fprintf(w, ", LAST ITERATIONS")
+		}
+		if pr.Global {
+			fmt.Fprintf(w, ", GLOBAL=YES")
+		}
+		fmt.Fprintf(w, "\n")
+		if 0 < len(pr.Options) {
+			fmt.Fprintf(w, "%s\n", outputList(pr.Options))
+		}
+	}
 }
 
+// Condition is initial conditions of keyword *INITIAL CONDITIONS
+//
+// Examples:
+//
+//	*INITIAL CONDITIONS,TYPE=TEMPERATURE
+//	NALL,20.
+//
+//	*INITIAL CONDITIONS,TYPE=FLUID VELOCITY
+//	Nall,1,1.
+//	Nall,2,0.
+//
+// First line:
+//
+//	*INITIAL CONDITIONS
+//	Enter the parameter TYPE and its value and, if needed,
+//	the parameter USER.
+//
+// Following line:
+//
+//	Data line, meaning of fields depends on TYPE.
 type Condition struct {
-	Type            string
+	Type string
+	User bool
+
+	// NodeSet and TemperatureNode is the first data line of
+	// initial temperature. Fields are filled by parser only and
+	// they are not written.
+	//
+	// Deprecated: use Lines.
 	NodeSet         string
 	TemperatureNode float64
+
+	// Lines is fields of data lines
+	Lines [][]string
 }
 
 func (c Condition) String() string {
+	return c.format(WriteOptions{})
+}
+
+func (c Condition) format(o WriteOptions) string {
 	if c.Type == "" {
 		return ""
 	}
@@ -310,133 +571,65 @@ func (c Condition) String() string {
 	if c.Type != "" {
 		out += fmt.Sprintf(", TYPE=%s", c.Type)
 	}
+	if c.User {
+		out += ", USER"
+	}
 	out += "\n"
-	out += fmt.Sprintf("%s, %.7e", c.NodeSet, c.TemperatureNode)
-	out += "\n"
+	for _, line := range c.Lines {
+		out += strings.Join(line, ", ") + "\n"
+	}
 	return out
 }
 
-func (f Model) String() string {
-	var buf bytes.Buffer
-
-	if f.Heading != "" {
-		fmt.Fprintf(&buf, "*Heading\n%s\n", f.Heading)
-	}
-	if len(f.Nodes) > 0 {
-		addHeader := true
-		for pos, node := range f.Nodes {
-			if addHeader {
-				fmt.Fprintf(&buf, "*NODE")
-				if node.Nodeset != "" {
-					fmt.Fprintf(&buf, ",NSET=%s", node.Nodeset)
-				}
-				fmt.Fprintf(&buf, "\n")
-				addHeader = false
-			}
-			fmt.Fprintf(&buf, "%5d, %+.12e, %+.12e, %+.12e\n",
-				node.Index, node.Coord[0], node.Coord[1], node.Coord[2])
-			if pos != len(f.Nodes)-1 {
-				if f.Nodes[pos].Nodeset != f.Nodes[pos+1].Nodeset {
-					addHeader = true
-				}
-			}
-		}
+func (f *Model) parseInitialConditions(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*INITIAL CONDITIONS") {
+		return false, nil
 	}
-	if len(f.Elements) > 0 {
-		addHeader := true
-		for pos, el := range f.Elements {
-			if len(el.Nodes) == 0 {
-				continue
-			}
-			if addHeader {
-				fmt.Fprintf(&buf, "*ELEMENT")
-				if el.Type != "" {
-					fmt.Fprintf(&buf, ", type=%s", el.Type)
-				}
-				if el.Elset != "" {
-					fmt.Fprintf(&buf, ", ELSET=%s", el.Elset)
-				}
-				fmt.Fprintf(&buf, "\n")
-				addHeader = false
-			}
-			fmt.Fprintf(&buf, "%5d,", el.Index)
-			for pos, v := range el.Nodes {
-				fmt.Fprintf(&buf, " %5d", v)
-				if pos != len(el.Nodes)-1 {
-					fmt.Fprintf(&buf, ",")
-				} else {
-					fmt.Fprintf(&buf, "\n")
-				}
-			}
-			if pos != len(f.Elements)-1 {
-				if f.Elements[pos].Type != f.Elements[pos+1].Type {
-					addHeader = true
-				}
-				if f.Elements[pos].Elset != f.Elements[pos+1].Elset {
-					addHeader = true
-				}
-			}
+	var c Condition
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "TYPE":
+			c.Type = value
+		case "USER":
+			c.User = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid initial conditions parameter: %s", part)
+			return
 		}
 	}
-	writeSet(&buf, "NSET", f.Nsets)
-	writeSet(&buf, "ELSET", f.Elsets)
-
-	for _, s := range f.Surfaces {
-		fmt.Fprintf(&buf, "%s", s)
-	}
-	fmt.Fprintf(&buf, "%s", f.InitialConditions.String())
-	for _, s := range f.SolidSections {
-		fmt.Fprintf(&buf, "%s", s.String())
-	}
-	for _, s := range f.ShellSections {
-		fmt.Fprintf(&buf, "%s", s.String())
-	}
-	for _, s := range f.BeamSections {
-		fmt.Fprintf(&buf, "%s", s.String())
-	}
-	for _, s := range f.Springs {
-		fmt.Fprintf(&buf, "%s", s.String())
+	if c.Type == "" {
+		err = fmt.Errorf("initial conditions must have parameter TYPE")
+		return
 	}
-
-	if f.TimePoint.Name != "" {
-		fmt.Fprintf(&buf, "*TIME POINTS, NAME=%s", f.TimePoint.Name)
-		if f.TimePoint.Generate {
-			fmt.Fprintf(&buf, ", GENERATE")
-		}
-		fmt.Fprintf(&buf, "\n")
-		for pos, t := range f.TimePoint.Time {
-			fmt.Fprintf(&buf, "%f ", t)
-			if pos != len(f.TimePoint.Time)-1 {
-				fmt.Fprintf(&buf, ",")
-			}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		for 0 < len(fs) && fs[len(fs)-1] == "" {
+			fs = fs[:len(fs)-1]
 		}
-		fmt.Fprintf(&buf, "\n")
+		c.Lines = append(c.Lines, fs)
 	}
-
-	for _, boun := range f.Boundaries {
-		if boun.LoadLocation == "" {
-			continue
+	// fill deprecated fields
+	if c.Type == "TEMPERATURE" && 0 < len(c.Lines) && len(c.Lines[0]) == 2 {
+		if t, errT := parseFloat(c.Lines[0][1]); errT == nil {
+			c.NodeSet, c.TemperatureNode = c.Lines[0][0], t
 		}
-		fmt.Fprintf(&buf, "*BOUNDARY\n%s,%d,%d,%.8e\n",
-			boun.LoadLocation, boun.Start, boun.Finish, boun.Factor,
-		)
-	}
-
-	for i := range f.RigidBodies {
-		fmt.Fprintf(&buf, "%s", f.RigidBodies[i])
 	}
-	for _, d := range f.DistributingCouplings {
-		fmt.Fprintf(&buf, "%s", d)
+	if len(f.Conditions) == 0 {
+		f.InitialConditions = c
 	}
+	f.Conditions = append(f.Conditions, c)
+	return true, nil
+}
 
-	for i := range f.Materials {
-		fmt.Fprintf(&buf, "%s", f.Materials[i].String())
-	}
-	for i := range f.Steps {
-		fmt.Fprintf(&buf, "%s", f.Steps[i].String())
+func (f Model) String() string {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf, WriteOptions{}); err != nil {
+		panic(err)
 	}
-
-	return strings.ToUpper(buf.String())
+	return buf.String()
 }
 
 type Temperature struct {
@@ -448,6 +641,10 @@ type Temperature struct {
 }
 
 func (t Temperature) String() string {
+	return t.format(WriteOptions{})
+}
+
+func (t Temperature) format(o WriteOptions) string {
 	if t.NodeSet == "" {
 		return ""
 	}
@@ -457,12 +654,12 @@ func (t Temperature) String() string {
 		out += "," + strings.Join(t.Parameters, ",")
 	}
 	out += "\n"
-	out += fmt.Sprintf("%s, %.7e", t.NodeSet, t.TemperatureNode)
+	out += fmt.Sprintf("%s, %s", t.NodeSet, o.float("%.7e", t.TemperatureNode))
 	if t.Gradient2 != 0 || t.Gradient1 != 0 {
-		out += fmt.Sprintf(" , %.7e", t.Gradient2)
+		out += fmt.Sprintf(" , %s", o.float("%.7e", t.Gradient2))
 	}
 	if t.Gradient1 != 0 {
-		out += fmt.Sprintf(" , %.7e", t.Gradient1)
+		out += fmt.Sprintf(" , %s", o.float("%.7e", t.Gradient1))
 	}
 	return out + "\n"
 }
@@ -611,7 +808,17 @@ func (f *Model) parseElement(block []string) (ok bool, err error) {
 		}
 	}
 
+	// element line is continued on next line after comma at the end
+	var lines []string
 	for _, line := range block[1:] {
+		if n := len(lines); 0 < n && strings.HasSuffix(strings.TrimSpace(lines[n-1]), ",") {
+			lines[n-1] += line
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	for _, line := range lines {
 		fs := fields(line)
 		var ints []int
 		for _, f := range fs {
@@ -644,6 +851,11 @@ type Set struct {
 }
 
 func (s Set) String(name string) string {
+	return s.format(name, 9)
+}
+
+// format return set with perLine entries per line
+func (s Set) format(name string, perLine int) string {
 	if len(s.Indexes) == 0 && len(s.Names) == 0 {
 		return "\n"
 	}
@@ -680,14 +892,14 @@ func (s Set) String(name string) string {
 		if i != len(list)-1 {
 			fmt.Fprintf(&buf, " , ")
 		}
-		if (i+1)%9 == 0 && i != len(list)-1 {
+		if (i+1)%perLine == 0 && i != len(list)-1 {
 			fmt.Fprintf(&buf, "\n")
 		}
 	}
 	return buf.String()
 }
 
-func writeSet(out io.Writer, name string, sets []Set) {
+func writeSet(out io.Writer, name string, sets []Set, perLine int) {
 	if len(sets) == 0 {
 		return
 	}
@@ -695,10 +907,103 @@ func writeSet(out io.Writer, name string, sets []Set) {
 		if len(s.Indexes) == 0 && len(s.Names) == 0 {
 			continue
 		}
-		fmt.Fprintf(out, "%s\n", s.String(name))
+		fmt.Fprintf(out, "%s\n", s.format(name, perLine))
 	}
 }
 
+// List return indexes of set with expanded GENERATE triples.
+// Names of other sets are not included.
+func (s Set) List() (list []int) {
+	if !s.Generate {
+		return append(list, s.Indexes...)
+	}
+	for i := 0; i+1 < len(s.Indexes); i += 3 {
+		start, end, inc := s.Indexes[i], s.Indexes[i+1], 1
+		if i+2 < len(s.Indexes) && 0 < s.Indexes[i+2] {
+			inc = s.Indexes[i+2]
+		}
+		for v := start; v <= end; v += inc {
+			list = append(list, v)
+		}
+	}
+	return
+}
+
+// NsetIndexes return indexes of nodes in node set.
+func (f Model) NsetIndexes(name string) (indexes []int, err error) {
+	return f.setIndexes(name, f.Nsets, func(name string) (list []int) {
+		for _, n := range f.Nodes {
+			if n.Nodeset == name {
+				list = append(list, n.Index)
+			}
+		}
+		return
+	}, map[string]bool{})
+}
+
+// ElsetIndexes return indexes of elements in element set.
+func (f Model) ElsetIndexes(name string) (indexes []int, err error) {
+	return f.setIndexes(name, f.Elsets, func(name string) (list []int) {
+		for _, el := range f.Elements {
+			if el.Elset == name {
+				list = append(list, el.Index)
+			}
+		}
+		return
+	}, map[string]bool{})
+}
+
+func (f Model) setIndexes(
+	name string,
+	sets []Set,
+	defined func(name string) []int,
+	visited map[string]bool,
+) (indexes []int, err error) {
+	if visited[name] {
+		return
+	}
+	visited[name] = true
+	indexes = defined(name)
+	found := 0 < len(indexes)
+	for _, s := range sets {
+		if s.Name != name {
+			continue
+		}
+		found = true
+		indexes = append(indexes, s.List()...)
+		for _, sub := range s.Names {
+			var list []int
+			list, err = f.setIndexes(sub, sets, defined, visited)
+			if err != nil {
+				return
+			}
+			indexes = append(indexes, list...)
+		}
+	}
+	if !found {
+		err = fmt.Errorf("not found set: %s", name)
+	}
+	return
+}
+
+// nodeLocation return indexes of nodes for location in data line.
+// Location is node number or node set label.
+func (f Model) nodeLocation(location string) (indexes []int, err error) {
+	if index, errInt := parseInt(location); errInt == nil {
+		return []int{index}, nil
+	}
+	return f.NsetIndexes(location)
+}
+
+// elementLocation return indexes of elements for location in data line.
+// Location is element number or element set label.
+func (f Model) elementLocation(location string) (indexes []int, err error) {
+	if index, errInt := parseInt(location); errInt == nil {
+		return []int{index}, nil
+	}
+	return f.ElsetIndexes(location)
+}
+
 func (f *Model) parseSet(s *[]Set, prefix string, block []string) (ok bool, err error) {
 	if !isHeader(block[0], "*"+prefix) {
 		return false, nil
@@ -759,10 +1064,7 @@ func (f *Model) parseDensity(block []string) (ok bool, err error) {
 	if err != nil {
 		return
 	}
-	if len(f.Materials) == 0 {
-		f.Materials = make([]Material, 1)
-	}
-	f.Materials[0].Density = ro
+	f.lastMaterial().Density = ro
 	return true, nil
 }
 
@@ -771,9 +1073,7 @@ func (f *Model) parseExpansion(block []string) (ok bool, err error) {
 		return false, nil
 	}
 	block = block[1:] // TODO for ZERO, TYPE
-	if len(f.Materials) == 0 {
-		f.Materials = make([]Material, 1)
-	}
+	m := f.lastMaterial()
 	for i := range block {
 		fs := strings.Fields(block[i])
 		var e Expansion
@@ -796,7 +1096,7 @@ func (f *Model) parseExpansion(block []string) (ok bool, err error) {
 			err = fmt.Errorf("Expansion: %v", fs)
 			return
 		}
-		f.Materials[0].Expansions = append(f.Materials[0].Expansions, e)
+		m.Expansions = append(m.Expansions, e)
 	}
 	return true, nil
 }
@@ -805,35 +1105,44 @@ func (f *Model) parseMaterial(block []string) (ok bool, err error) {
 	if !isHeader(block[0], "*MATERIAL") {
 		return false, nil
 	}
-	line := block[0]
-	line = strings.Replace(line, ",", " ", -1)
-	fields := strings.Fields(line)
-	if len(fields) > 1 {
-		for _, field := range fields[1:] {
-			switch {
-			case strings.HasPrefix(field, "NAME="):
-				if len(f.Materials) == 0 {
-					f.Materials = make([]Material, 1)
-				}
-				f.Materials[0].Name = field[5:]
-			default:
-				panic(fmt.Errorf("`%s` : `%s`", line, field))
-			}
+	var m Material
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NAME":
+			m.Name = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid material parameter: %s", part)
+			return
 		}
 	}
+	f.Materials = append(f.Materials, m)
 	return true, nil
 }
 
+// lastMaterial return last material of model. Material sub-keywords,
+// for example *ELASTIC, are added to that material.
+func (f *Model) lastMaterial() *Material {
+	if len(f.Materials) == 0 {
+		f.Materials = make([]Material, 1)
+	}
+	return &f.Materials[len(f.Materials)-1]
+}
+
 func (f *Model) parseElastic(block []string) (ok bool, err error) {
 	if !isHeader(block[0], "*ELASTIC") {
 		return false, nil
 	}
-	line := strings.Replace(block[1], ",", " ", -1)
-	fields := strings.Fields(line)
-	if len(f.Materials) == 0 {
-		f.Materials = make([]Material, 1)
+	m := f.lastMaterial()
+	for _, part := range fields(block[0])[1:] {
+		if key, value := keyValue(part); key == "TYPE" {
+			m.ElasticType = value
+		}
 	}
 	for pos := 1; pos < len(block); pos++ {
+		fields := strings.Fields(strings.Replace(block[pos], ",", " ", -1))
 		var pr Property
 		switch len(fields) {
 		case 3:
@@ -852,7 +1161,7 @@ func (f *Model) parseElastic(block []string) (ok bool, err error) {
 				return
 			}
 		}
-		f.Materials[0].Properties = append(f.Materials[0].Properties, pr)
+		m.Properties = append(m.Properties, pr)
 	}
 	return true, nil
 }
@@ -876,6 +1185,10 @@ type Spring struct {
 }
 
 func (s Spring) String() string {
+	return s.format(WriteOptions{})
+}
+
+func (s Spring) format(o WriteOptions) string {
 	var out string
 	out += fmt.Sprintf("*SPRING,ELSET=%s\n", s.ElsetName)
 	if 0 < s.Freedom[0] && 0 < s.Freedom[1] {
@@ -885,7 +1198,7 @@ func (s Spring) String() string {
 	} else {
 		out += "\n"
 	}
-	out += fmt.Sprintf("%.7e\n", s.SpringConstant)
+	out += fmt.Sprintf("%s\n", o.float("%.7e", s.SpringConstant))
 	return out
 }
 
@@ -912,44 +1225,6 @@ func (r RigidBody) String() string {
 	return out
 }
 
-// First line:
-//
-//	*DISTRIBUTING COUPLING
-//	Enter the ELSET parameter and its value
-//
-// Following line:
-//
-//	Node number or node set
-//	Weight
-//
-// Repeat this line if needed.
-type DistributingCoupling struct {
-	ElsetName     string
-	ElsetNode     int
-	NodeIndexes   []int // with weight 1.0
-	NodeNames     []string
-	ReferenceNode int
-}
-
-func (d DistributingCoupling) String() string {
-	if d.ElsetName == "" {
-		return "\n"
-	}
-	var buf bytes.Buffer
-	fmt.Fprintf(&buf, "*DISTRIBUTING COUPLING,ELSET=%s\n", d.ElsetName)
-	for _, n := range d.NodeIndexes {
-		fmt.Fprintf(&buf, "%d,1.\n", n)
-	}
-	for _, n := range d.NodeNames {
-		fmt.Fprintf(&buf, "%s,1.\n", n)
-	}
-	fmt.Fprintf(&buf, "*ELSET,ELSET=%s\n", d.ElsetName)
-	fmt.Fprintf(&buf, "%d\n", d.ElsetNode)
-	fmt.Fprintf(&buf, "*ELEMENT,TYPE=DCOUP3D\n")
-	fmt.Fprintf(&buf, "%d, %d\n", d.ElsetNode, d.ReferenceNode)
-	return buf.String()
-}
-
 // Boundary for structures:
 // – 1: translation in the local x-direction
 // – 2: translation in the local y-direction
@@ -959,21 +1234,143 @@ func (d DistributingCoupling) String() string {
 // – 6: rotation about the local z-axis (only for nodes belonging to beams or shells)
 // – 11: temperature
 //
+// Examples:
+//
+//	*BOUNDARY
+//	1,1,3
+//
+//	*BOUNDARY,OP=NEW,AMPLITUDE=A1
+//	NFIX,3,3,0.1
+//
+//	*BOUNDARY
+//	NFIX,ENCASTRE
+//
 // First line:
 //
 //	*BOUNDARY
-//	Enter any needed parameters and their value.
+//	Enter any needed parameters and their value:
+//	OP (NEW or MOD), AMPLITUDE, TIME DELAY, LOAD CASE, FIXED, USER,
+//	MASS FLOW, SUBMODEL, STEP, DATA SET,
+//	TYPE (DISPLACEMENT, VELOCITY or ACCELERATION).
 //
 // Following line:
 //
 //	Node number or node set label
 //	First degree of freedom constrained
 //	Last degree of freedom constrained. This field may be left blank if only one degree of freedom is constrained.
+//	Actual magnitude of the prescribed displacement (default 0).
+//
+// Instead of degrees of freedom a named boundary type such as
+// ENCASTRE, PINNED, XSYMM, YSYMM, ZSYMM, XASYMM, YASYMM or ZASYMM is
+// acceptable.
 type Boundary struct {
 	LoadLocation string
 	Start        int
 	Finish       int
 	Factor       float64
+	Named        string // named boundary type, for example: ENCASTRE
+
+	BoundaryOptions
+
+	block int // sequence number of source block
+}
+
+// BoundaryOptions is parameters of *BOUNDARY block
+type BoundaryOptions struct {
+	LoadOptions
+	LoadCase int // 1 - real part, 2 - imaginary part
+	Fixed    bool
+	MassFlow bool
+	Submodel bool
+	Step     int // step of global model, only for SUBMODEL
+	DataSet  int // data set of global model, only for SUBMODEL
+	Type     string
+}
+
+func (o BoundaryOptions) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s", o.LoadOptions)
+	if o.LoadCase != 0 {
+		fmt.Fprintf(&buf, ", LOAD CASE=%d", o.LoadCase)
+	}
+	if o.Fixed {
+		fmt.Fprintf(&buf, ", FIXED")
+	}
+	if o.MassFlow {
+		fmt.Fprintf(&buf, ", MASS FLOW")
+	}
+	if o.Submodel {
+		fmt.Fprintf(&buf, ", SUBMODEL")
+	}
+	if o.Step != 0 {
+		fmt.Fprintf(&buf, ", STEP=%d", o.Step)
+	}
+	if o.DataSet != 0 {
+		fmt.Fprintf(&buf, ", DATA SET=%d", o.DataSet)
+	}
+	if o.Type != "" {
+		fmt.Fprintf(&buf, ", TYPE=%s", o.Type)
+	}
+	return buf.String()
+}
+
+// namedBoundaries is degrees of freedom of named boundary types
+var namedBoundaries = map[string][]int{
+	"ENCASTRE": {1, 2, 3, 4, 5, 6},
+	"PINNED":   {1, 2, 3},
+	"XSYMM":    {1, 5, 6},
+	"YSYMM":    {2, 4, 6},
+	"ZSYMM":    {3, 4, 5},
+	"XASYMM":   {2, 3, 4},
+	"YASYMM":   {1, 3, 5},
+	"ZASYMM":   {1, 2, 6},
+}
+
+// Dofs return constrained degrees of freedom of boundary
+func (b Boundary) Dofs() (dofs []int) {
+	if b.Named != "" {
+		return namedBoundaries[b.Named]
+	}
+	finish := b.Finish
+	if finish == 0 {
+		finish = b.Start
+	}
+	for dof := b.Start; dof <= finish; dof++ {
+		dofs = append(dofs, dof)
+	}
+	return
+}
+
+func (b Boundary) line() string {
+	if b.Named != "" {
+		return fmt.Sprintf("%s, %s", b.LoadLocation, b.Named)
+	}
+	line := fmt.Sprintf("%s, %d", b.LoadLocation, b.Start)
+	if b.Finish != 0 || b.Factor != 0 {
+		line += ", "
+		if b.Finish != 0 {
+			line += strconv.Itoa(b.Finish)
+		}
+	}
+	if b.Factor != 0 {
+		line += ", " + efmt.Sprint(b.Factor)
+	}
+	return line
+}
+
+// writeBoundaries write boundaries. Rows with same parameters and of
+// the same block are written in one *BOUNDARY block. Boundary without
+// location is written as block without rows.
+func writeBoundaries(buf *bytes.Buffer, bs []Boundary) {
+	for i, b := range bs {
+		if i == 0 || b.BoundaryOptions != bs[i-1].BoundaryOptions ||
+			b.block != bs[i-1].block || bs[i-1].LoadLocation == "" {
+			fmt.Fprintf(buf, "*BOUNDARY%s\n", b.BoundaryOptions)
+		}
+		if b.LoadLocation != "" {
+			fmt.Fprintf(buf, "%s\n", b.line())
+		}
+	}
 }
 
 func parseBoundary(bs *[]Boundary) func(block []string) (ok bool, err error) {
@@ -982,32 +1379,65 @@ func parseBoundary(bs *[]Boundary) func(block []string) (ok bool, err error) {
 		if !isHeader(block[0], "*BOUNDARY") {
 			return false, nil
 		}
-		var b Boundary
+		var o BoundaryOptions
+		for _, part := range fields(block[0])[1:] {
+			key, value := keyValue(part)
+			if ok, err = o.LoadOptions.parse(key, value); err != nil {
+				return
+			} else if ok || key == "" {
+				continue
+			}
+			switch key {
+			case "LOAD CASE":
+				o.LoadCase, err = parseInt(value)
+			case "FIXED":
+				o.Fixed = true
+			case "MASS FLOW":
+				o.MassFlow = true
+			case "SUBMODEL":
+				o.Submodel = true
+			case "STEP":
+				o.Step, err = parseInt(value)
+			case "DATA SET":
+				o.DataSet, err = parseInt(value)
+			case "TYPE":
+				o.Type = value
+			default:
+				err = fmt.Errorf("not valid boundary parameter: %s", part)
+			}
+			if err != nil {
+				return false, err
+			}
+		}
+		var number int
+		if n := len(*bs); 0 < n {
+			number = (*bs)[n-1].block + 1
+		}
+		// block without rows is acceptable, for example
+		// *BOUNDARY,OP=NEW for removing all boundaries
+		if len(block) == 1 {
+			*bs = append(*bs, Boundary{BoundaryOptions: o, block: number})
+		}
 		for _, line := range block[1:] {
-			line = strings.Replace(line, ",", " ", -1)
-			fields := strings.Fields(line)
-			b.LoadLocation = fields[0]
+			fs := fields(line)
+			b := Boundary{LoadLocation: fs[0], BoundaryOptions: o, block: number}
 
-			var i64 int64
-
-			if len(fields) > 1 {
-				i64, err = strconv.ParseInt(fields[1], 10, 64)
-				if err != nil {
+			if len(fs) > 1 {
+				if _, named := namedBoundaries[strings.ToUpper(fs[1])]; named {
+					b.Named = strings.ToUpper(fs[1])
+				} else if b.Start, err = parseInt(fs[1]); err != nil {
 					return
 				}
-				b.Start = int(i64)
 			}
 
-			if len(fields) > 2 {
-				i64, err = strconv.ParseInt(fields[2], 10, 64)
-				if err != nil {
+			if len(fs) > 2 && fs[2] != "" {
+				if b.Finish, err = parseInt(fs[2]); err != nil {
 					return
 				}
-				b.Finish = int(i64)
 			}
 
-			if len(fields) > 3 {
-				b.Factor, err = parseFloat(fields[3])
+			if len(fs) > 3 && fs[3] != "" {
+				b.Factor, err = parseFloat(fs[3])
 				if err != nil {
 					return
 				}
@@ -1022,8 +1452,9 @@ func parseBoundary(bs *[]Boundary) func(block []string) (ok bool, err error) {
 
 // *SOLID SECTION,ELSET=EALL,MATERIAL=HY
 type SolidSection struct {
-	Elset    string
-	Material string
+	Elset       string
+	Material    string
+	Orientation string
 }
 
 func (ss SolidSection) String() string {
@@ -1034,6 +1465,9 @@ func (ss SolidSection) String() string {
 	fmt.Fprintf(&buf, "*SOLID SECTION")
 	fmt.Fprintf(&buf, ", ELSET=%s", ss.Elset)
 	fmt.Fprintf(&buf, ", MATERIAL=%s", ss.Material)
+	if ss.Orientation != "" {
+		fmt.Fprintf(&buf, ", ORIENTATION=%s", ss.Orientation)
+	}
 	fmt.Fprintf(&buf, "\n")
 	return buf.String()
 }
@@ -1055,6 +1489,10 @@ func (f *Model) parseSolidSection(block []string) (ok bool, err error) {
 			index := strings.Index(s, "=")
 			s = strings.TrimSpace(s[index+1:])
 			ss.Elset = s
+		case strings.HasPrefix(s, "ORIENTATION"):
+			index := strings.Index(s, "=")
+			s = strings.TrimSpace(s[index+1:])
+			ss.Orientation = s
 		case s == "":
 			// do nothing
 		default:
@@ -1072,39 +1510,115 @@ func (f *Model) parseSolidSection(block []string) (ok bool, err error) {
 	return true, nil
 }
 
+// BeamSection is keywords *BEAM SECTION and *BEAM GENERAL SECTION.
+//
+// Examples:
+//
+//	*BEAM SECTION,ELSET=EBEAM,MATERIAL=EL,SECTION=RECT
+//	0.05,0.10
+//	0.,0.,1.
+//
+//	*BEAM SECTION,ELSET=EAll,MATERIAL=ALUM,SECTION=PIPE
+//	.11,.01
+//	1.d0,0.d0,0.d0
+//
+//	*BEAM GENERAL SECTION,ELSET=EAll,MATERIAL=EL,SECTION=GENERAL
+//	0.0625,3.2552e-4,0.,3.2552e-4,5.5e-4
+//	0.,1.,0.
+//
+//	*BEAM SECTION,ELSET=E1,MATERIAL=ALUM,SECTION=RECT,NODAL THICKNESS
+//	.25,.25
+//	1.d0,0.d0,0.d0
+//
+// First line:
+//
+//	*BEAM SECTION or *BEAM GENERAL SECTION
+//	Enter the parameters ELSET, MATERIAL and SECTION and their values,
+//	and, if necessary, the parameters OFFSET1, OFFSET2 and
+//	NODAL THICKNESS. Section RECTANGULAR is the same as RECT and
+//	section CIRCULAR is the same as CIRC.
+//
+// Second line (dimensions of section, local 1-direction is y and
+// local 2-direction is z):
+//
+//	RECT    - thickness in 1-direction, thickness in 2-direction.
+//	CIRC    - radius in 1-direction, radius in 2-direction.
+//	PIPE    - outer radius, wall thickness.
+//	BOX     - length in 1-direction, length in 2-direction, wall
+//	          thicknesses t1 (at +1), t2 (at +2), t3 (at -1), t4 (at -2).
+//	I       - distance from bottom to origin l, height h (in 2-direction),
+//	          bottom flange width b1, top flange width b2, bottom flange
+//	          thickness t1, top flange thickness t2, web thickness t3.
+//	GENERAL - area, I11, I12, I22 and, for *BEAM SECTION, shear
+//	          correction factor or, for *BEAM GENERAL SECTION,
+//	          torsional constant.
+//
+// Third line:
+//
+//	Direction of local 1-axis (optional).
 type BeamSection struct {
+	General  bool // *BEAM GENERAL SECTION
 	Section  string
 	Elset    string
 	Material string
 
 	Offset1, Offset2 float64
+	NodalThickness   bool // thicknesses are defined by *NODAL THICKNESS
+
+	Dimensions []float64
+	Vector     [3]float64
+
+	// Deprecated: use Dimensions. Thks is first two dimensions after
+	// parsing and it is used only if Dimensions is empty.
+	Thks [2]float64
+}
 
-	Thks   [2]float64
-	Vector [3]float64
+// dimensions return dimensions of section
+func (b BeamSection) dimensions() []float64 {
+	if len(b.Dimensions) == 0 && b.Thks != [2]float64{} {
+		return b.Thks[:]
+	}
+	return b.Dimensions
 }
 
 func (b BeamSection) String() string {
+	return b.format(WriteOptions{})
+}
+
+func (b BeamSection) format(o WriteOptions) string {
 	var buf bytes.Buffer
-	fmt.Fprintf(&buf, "*BEAM SECTION")
+	if b.General {
+		fmt.Fprintf(&buf, "*BEAM GENERAL SECTION")
+	} else {
+		fmt.Fprintf(&buf, "*BEAM SECTION")
+	}
 	fmt.Fprintf(&buf, ", SECTION=%s", b.Section)
 	fmt.Fprintf(&buf, ", ELSET=%s", b.Elset)
 	fmt.Fprintf(&buf, ", MATERIAL=%s", b.Material)
 	if 1e-5 < math.Abs(b.Offset1) {
-		fmt.Fprintf(&buf, ", OFFSET1=%.12e", b.Offset1)
+		fmt.Fprintf(&buf, ", OFFSET1=%s", o.float("%.12e", b.Offset1))
 	}
 	if 1e-5 < math.Abs(b.Offset2) {
-		fmt.Fprintf(&buf, ", OFFSET2=%.12e", b.Offset2)
+		fmt.Fprintf(&buf, ", OFFSET2=%s", o.float("%.12e", b.Offset2))
+	}
+	if b.NodalThickness {
+		fmt.Fprintf(&buf, ", NODAL THICKNESS")
 	}
 	fmt.Fprintf(&buf, "\n")
-	for iv, v := range b.Thks {
-		fmt.Fprintf(&buf, "%.7e", v)
-		if iv != len(b.Thks)-1 {
+	dimensions := b.dimensions()
+	for iv, v := range dimensions {
+		fmt.Fprintf(&buf, "%s", efmt.Sprint(v))
+		if iv == len(dimensions)-1 || (iv+1)%8 == 0 {
+			fmt.Fprintf(&buf, "\n")
+		} else {
 			fmt.Fprintf(&buf, ",")
 		}
 	}
-	fmt.Fprintf(&buf, "\n")
+	if b.Vector == [3]float64{} {
+		return buf.String()
+	}
 	for iv, v := range b.Vector {
-		fmt.Fprintf(&buf, "%.7e", v)
+		fmt.Fprintf(&buf, "%s", o.float("%.7e", v))
 		if iv != len(b.Vector)-1 {
 			fmt.Fprintf(&buf, ",")
 		}
@@ -1113,6 +1627,16 @@ func (b BeamSection) String() string {
 	return buf.String()
 }
 
+// beamSectionDimensions is amount of dimensions for section types
+var beamSectionDimensions = map[string]int{
+	"RECT":    2,
+	"CIRC":    2,
+	"PIPE":    2,
+	"BOX":     6,
+	"I":       7,
+	"GENERAL": 5,
+}
+
 // [*BEAM SECTION, SECTION=RECT, ELSET=LINKS, MATERIAL=STEEL 10.0, 10.0 0.0, 1.0, 0.0]
 // [*BEAM SECTION, SECTION=RECT, ELSET=RECHTS, MATERIAL=STEEL 5.0, 5.0 0.0, 1.0, 0.0]
 // [*BEAM SECTION,ELSET=SET1,MATERIAL=EL,SECTION=RECT 0.05, 0.08 0.D0,1.D0,0.D0]
@@ -1120,44 +1644,82 @@ func (b BeamSection) String() string {
 // [*BEAM SECTION,ELSET=EBEAM,MATERIAL=EL,SECTION=RECT 0.05,0.10 0.,0.,1.]
 // [*BEAM SECTION,ELSET=EBEAM,MATERIAL=EL,SECTION=RECT 0.05,0.10 0.,0.,1.]
 func (f *Model) parseBeamSection(block []string) (ok bool, err error) {
-	if !isHeader(block[0], "*BEAM SECTION") {
+	var b BeamSection
+	switch {
+	case isHeader(block[0], "*BEAM SECTION"):
+	case isHeader(block[0], "*BEAM GENERAL SECTION"):
+		b.General = true
+		b.Section = "GENERAL"
+	default:
 		return false, nil
 	}
-	if len(block) != 3 {
-		return false, fmt.Errorf("not valid *BEAM SECTION")
-	}
-	var b BeamSection
 	split := fields(block[0])[1:]
 	for _, s := range split {
-		s = strings.TrimSpace(s)
-		switch {
-		case strings.HasPrefix(s, "MATERIAL"):
-			index := strings.Index(s, "=")
-			s = strings.TrimSpace(s[index+1:])
-			b.Material = s
-		case strings.HasPrefix(s, "SECTION"):
-			index := strings.Index(s, "=")
-			s = strings.TrimSpace(s[index+1:])
-			b.Section = s
-		case strings.HasPrefix(s, "ELSET"):
-			index := strings.Index(s, "=")
-			s = strings.TrimSpace(s[index+1:])
-			b.Elset = s
-		case s == "":
+		key, value := keyValue(s)
+		switch key {
+		case "MATERIAL":
+			b.Material = value
+		case "SECTION":
+			b.Section = strings.ToUpper(value)
+			switch b.Section {
+			case "RECTANGULAR":
+				b.Section = "RECT"
+			case "CIRCULAR":
+				b.Section = "CIRC"
+			}
+		case "ELSET":
+			b.Elset = value
+		case "NODAL THICKNESS":
+			b.NodalThickness = true
+		case "OFFSET1":
+			b.Offset1, err = parseFloat(value)
+		case "OFFSET2":
+			b.Offset2, err = parseFloat(value)
+		case "":
 			// do nothing
 		default:
-			panic(fmt.Errorf("%s", strings.Join(split, "|")))
+			err = fmt.Errorf("not valid beam section parameter: %s", s)
+		}
+		if err != nil {
+			return
 		}
 	}
-	for i, s := range fields(block[1]) {
-		var v float64
-		v, err = parseFloat(s)
+	amount, ok := beamSectionDimensions[b.Section]
+	if !ok {
+		return false, fmt.Errorf("not valid beam section type: %s", b.Section)
+	}
+	// dimensions may be written on several full lines
+	// with 8 entries per line
+	block = block[1:]
+	for 0 < len(block) && len(b.Dimensions) < amount {
+		var vs []float64
+		vs, err = parseFloats(block[0])
 		if err != nil {
 			return
 		}
-		b.Thks[i] = v
+		b.Dimensions = append(b.Dimensions, vs...)
+		block = block[1:]
+		if len(vs) < 8 {
+			break
+		}
+	}
+	for 0 < len(b.Dimensions) && b.Dimensions[len(b.Dimensions)-1] == 0 &&
+		amount < len(b.Dimensions) {
+		b.Dimensions = b.Dimensions[:len(b.Dimensions)-1]
+	}
+	if len(b.Dimensions) == 0 || 1 < len(block) {
+		return false, fmt.Errorf("not valid *BEAM SECTION")
+	}
+	copy(b.Thks[:], b.Dimensions)
+	if len(block) == 0 {
+		// default direction of local 1-axis
+		f.BeamSections = append(f.BeamSections, b)
+		return true, nil
 	}
-	for i, s := range fields(block[2]) {
+	for i, s := range fields(block[0]) {
+		if 3 <= i {
+			break
+		}
 		var v float64
 		v, err = parseFloat(s)
 		if err != nil {
@@ -1170,38 +1732,95 @@ func (f *Model) parseBeamSection(block []string) (ok bool, err error) {
 	return true, nil
 }
 
+// ShellSection
+//
+// Examples:
+//
+//	*SHELL SECTION,MATERIAL=steel,ELSET=Eall,OFFSET=0
+//	6.2500E-02
+//
+//	*SHELL SECTION,ELSET=Eall,COMPOSITE
+//	0.1,,STEEL,OR1
+//	0.2,,ALU,OR2
+//
+// First line:
+//
+//	*SHELL SECTION
+//	Enter the parameter ELSET and its value, and, if necessary, the
+//	parameters MATERIAL, ORIENTATION, OFFSET, NODAL THICKNESS and COMPOSITE.
+//
+// Second line (without COMPOSITE):
+//
+//	Thickness.
+//
+// Following line (with COMPOSITE, one line per ply):
+//
+//	Thickness of ply.
+//	Number of integration points (not used by CalculiX).
+//	Material of ply.
+//	Orientation of ply.
 type ShellSection struct {
 	Elset          string
 	Offset         float64
 	Composite      bool
 	NodalThickness bool
-	Property       [12]struct {
-		Thickness float64
-		Material  string
+	Orientation    string
+	Plies          []Ply // only one ply without COMPOSITE
+}
+
+// Ply is layer of shell section
+type Ply struct {
+	Thickness         float64
+	IntegrationPoints int
+	Material          string
+	Orientation       string
+}
+
+// TotalThickness return sum of plies thicknesses
+func (ss ShellSection) TotalThickness() (t float64) {
+	for _, p := range ss.Plies {
+		t += p.Thickness
 	}
+	return
 }
 
 func (ss ShellSection) String() string {
+	return ss.format(WriteOptions{})
+}
+
+func (ss ShellSection) format(o WriteOptions) string {
 	var buf bytes.Buffer
 	fmt.Fprintf(&buf, "*SHELL SECTION")
 	fmt.Fprintf(&buf, ", ELSET=%s", ss.Elset)
-	fmt.Fprintf(&buf, ", OFFSET=%f", ss.Offset)
+	fmt.Fprintf(&buf, ", OFFSET=%s", o.float("%f", ss.Offset))
 	if ss.NodalThickness {
 		fmt.Fprintf(&buf, ", NODAL THICKNESS")
 	}
+	if ss.Orientation != "" {
+		fmt.Fprintf(&buf, ", ORIENTATION=%s", ss.Orientation)
+	}
 	if ss.Composite {
 		fmt.Fprintf(&buf, ", COMPOSITE")
 		fmt.Fprintf(&buf, "\n")
-		for _, row := range ss.Property {
-			if math.Abs(row.Thickness) < 1e-8 {
-				continue
+		for _, p := range ss.Plies {
+			fmt.Fprintf(&buf, "%s, ", o.float("%.8e", p.Thickness))
+			if p.IntegrationPoints != 0 {
+				fmt.Fprintf(&buf, "%d", p.IntegrationPoints)
+			}
+			fmt.Fprintf(&buf, ", %s", p.Material)
+			if p.Orientation != "" {
+				fmt.Fprintf(&buf, ", %s", p.Orientation)
 			}
-			fmt.Fprintf(&buf, "%.8e,, %s\n", row.Thickness, row.Material)
+			fmt.Fprintf(&buf, "\n")
 		}
 	} else {
-		fmt.Fprintf(&buf, ", MATERIAL=%s", ss.Property[0].Material)
+		var p Ply
+		if 0 < len(ss.Plies) {
+			p = ss.Plies[0]
+		}
+		fmt.Fprintf(&buf, ", MATERIAL=%s", p.Material)
 		fmt.Fprintf(&buf, "\n")
-		fmt.Fprintf(&buf, "%.8f\n", ss.Property[0].Thickness)
+		fmt.Fprintf(&buf, "%s\n", o.float("%.8f", p.Thickness))
 	}
 	return buf.String()
 }
@@ -1213,6 +1832,7 @@ func (f *Model) parseShellSection(block []string) (ok bool, err error) {
 		return false, nil
 	}
 	var ss ShellSection
+	var material string
 	split := fields(block[0])[1:]
 	for _, s := range split {
 		s = strings.TrimSpace(s)
@@ -1220,7 +1840,7 @@ func (f *Model) parseShellSection(block []string) (ok bool, err error) {
 		case strings.HasPrefix(s, "MATERIAL"):
 			index := strings.Index(s, "=")
 			s = strings.TrimSpace(s[index+1:])
-			ss.Property[0].Material = s
+			material = s
 		case strings.HasPrefix(s, "ELSET"):
 			index := strings.Index(s, "=")
 			s = strings.TrimSpace(s[index+1:])
@@ -1234,6 +1854,10 @@ func (f *Model) parseShellSection(block []string) (ok bool, err error) {
 			}
 		case strings.HasPrefix(s, "NODAL THICKNESS"):
 			ss.NodalThickness = true
+		case strings.HasPrefix(s, "ORIENTATION"):
+			index := strings.Index(s, "=")
+			s = strings.TrimSpace(s[index+1:])
+			ss.Orientation = s
 		case s == "":
 			// do nothing
 		case s == "COMPOSITE":
@@ -1243,29 +1867,94 @@ func (f *Model) parseShellSection(block []string) (ok bool, err error) {
 		}
 	}
 	if ss.Composite {
-		for pos, line := range block[1:] {
-			line = strings.Replace(line, ",", " ", -1)
-			fields := strings.Fields(line)
-			ss.Property[pos].Thickness, err = parseFloat(fields[0])
+		for _, line := range block[1:] {
+			fs := append(fields(line), "", "", "")
+			var p Ply
+			p.Thickness, err = parseFloat(fs[0])
 			if err != nil {
 				err = fmt.Errorf("%v : %v", block, err)
 				return
 			}
-			ss.Property[pos].Material = fields[1]
+			if fs[1] != "" {
+				p.IntegrationPoints, err = parseInt(fs[1])
+				if err != nil {
+					err = fmt.Errorf("%v : %v", block, err)
+					return
+				}
+			}
+			p.Material, p.Orientation = fs[2], fs[3]
+			ss.Plies = append(ss.Plies, p)
 		}
 	} else {
-		line := strings.TrimSpace(block[1])
-		ss.Property[0].Thickness, err = parseFloat(line)
+		if len(block) != 2 {
+			err = fmt.Errorf("not valid shell section: %v", block)
+			return
+		}
+		p := Ply{Material: material}
+		p.Thickness, err = parseFloat(fields(block[1])[0])
 		if err != nil {
 			err = fmt.Errorf("%v : %v", block, err)
 			return
 		}
+		ss.Plies = append(ss.Plies, p)
 	}
 	f.ShellSections = append(f.ShellSections, ss)
 
 	return true, nil
 }
 
+// ABD return laminate stiffness matrices of shell section: extensional
+// stiffness A, coupling stiffness B and bending stiffness D in order
+// 11, 22, 12. Matrices are calculated for reference surface defined by
+// OFFSET. Ply materials must have isotropic elastic properties, so
+// orientations of plies are not influent on stiffness, otherwise error
+// is returned. For temperature dependent materials the first elastic
+// properties are used.
+func (f Model) ABD(ss ShellSection) (a, b, d [3][3]float64, err error) {
+	h := ss.TotalThickness()
+	z := -h/2 - ss.Offset*h
+	for _, p := range ss.Plies {
+		var m *Material
+		for i := range f.Materials {
+			if strings.EqualFold(f.Materials[i].Name, p.Material) {
+				m = &f.Materials[i]
+				break
+			}
+		}
+		if m == nil {
+			err = fmt.Errorf("not found material %s", p.Material)
+			return
+		}
+		if len(m.Properties) == 0 {
+			err = fmt.Errorf("material %s has not elastic properties", p.Material)
+			return
+		}
+		switch m.ElasticType {
+		case "", "ISO", "ISOTROPIC":
+		default:
+			err = fmt.Errorf("material %s has not isotropic elastic properties: TYPE=%s",
+				p.Material, m.ElasticType)
+			return
+		}
+		e, v := m.Properties[0].E, m.Properties[0].V
+		q := [3][3]float64{
+			{e / (1 - v*v), v * e / (1 - v*v), 0},
+			{v * e / (1 - v*v), e / (1 - v*v), 0},
+			{0, 0, e / (2 * (1 + v))},
+		}
+		z1 := z + p.Thickness
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				a[i][j] += q[i][j] * (z1 - z)
+				b[i][j] += q[i][j] * (z1*z1 - z*z) / 2
+				d[i][j] += q[i][j] * (z1*z1*z1 - z*z*z) / 3
+			}
+		}
+		z = z1
+	}
+	return
+}
+
 // Examples:
 //
 // *STEP
@@ -1286,13 +1975,13 @@ func (f *Model) parseShellSection(block []string) (ok bool, err error) {
 // *EL PRINT,ELSET=EALL
 // S
 // *END STEP
-func (f *Model) parseStep(block []string) (ok bool, err error) {
+func (f *Model) parseStep(block []string) (ok bool, keywords []blockRanges, err error) {
 	var s Step
 	if !isHeader(block[0], "*STEP") {
-		return false, nil
+		return false, nil, nil
 	}
 	if !isHeader(block[len(block)-1], "*END STEP") {
-		return false, nil
+		return false, nil, nil
 	}
 	defer func() {
 		f.Steps = append(f.Steps, s)
@@ -1332,11 +2021,27 @@ func (f *Model) parseStep(block []string) (ok bool, err error) {
 	block = block[1 : len(block)-1]
 	blocks := splitByBlocks(block)
 
+	groups := s.itemGroups(WriteOptions{})
+	var prev []Range // ranges of previous keyword
 	et := errors.New("parse step")
 	for _, block := range blocks {
+		state := newBlockState(groups, prev)
 		err := blockParser(block, []func(block []string) (ok bool, err error){
 			s.parseBuckle,
+			s.parseFrequency,
+			s.parseDynamic,
+			s.parseModalDynamic,
+			s.parseSteadyStateDynamics,
+			s.parseModalDamping,
 			s.parseStatic,
+			s.parseHeatTransfer,
+			s.parseModelChange,
+			s.parseCyclicSymmetryModes,
+			s.parseSensitivity,
+			s.parseFeasibleDirection,
+			s.parseObjective,
+			s.parseConstraint,
+			s.parseFilter,
 			func(block []string) (ok bool, err error) {
 				return s.parsePrint(block, "*NODE FILE", &(s.NodeFiles))
 			},
@@ -1349,13 +2054,37 @@ func (f *Model) parseStep(block []string) (ok bool, err error) {
 			func(block []string) (ok bool, err error) {
 				return s.parsePrint(block, "*EL PRINT", &(s.ElPrints))
 			},
+			func(block []string) (ok bool, err error) {
+				return s.parsePrint(block, "*ELEMENT OUTPUT", &(s.ElementOutputs))
+			},
+			func(block []string) (ok bool, err error) {
+				return s.parsePrint(block, "*CONTACT FILE", &(s.ContactFiles))
+			},
+			func(block []string) (ok bool, err error) {
+				return s.parsePrint(block, "*CONTACT PRINT", &(s.ContactPrints))
+			},
+			s.parseSectionPrint,
+			s.parseControls,
+			parseRestart(&s.Restart),
 			s.parseCload,
 			s.parseDload,
+			s.parseDsload,
+			s.parseDflux,
+			s.parseCflux,
+			s.parseFilm,
+			s.parseRadiate,
+			s.parseBoundaryF,
+			s.parseMassFlow,
 			parseBoundary(&s.Boundaries),
 		})
 		if err != nil {
 			_ = et.Add(err)
 		}
+		r := state.changed(groups)
+		keywords = append(keywords, r)
+		if !r.part {
+			prev = r.ranges
+		}
 	}
 	if et.IsError() {
 		err = et
@@ -1366,9 +2095,12 @@ func (f *Model) parseStep(block []string) (ok bool, err error) {
 	return
 }
 
+// Buckle
+//
 // First line:
 //
 //	*BUCKLE
+//	Enter the parameter SOLVER and its value, if needed.
 //
 // Second line:
 //
@@ -1376,28 +2108,79 @@ func (f *Model) parseStep(block []string) (ok bool, err error) {
 //	Accuracy desired (default: 0.01).
 //	# Lanczos vectors calculated in each iteration (default: 4 * #eigenvalues).
 //	Maximum # of iterations (default: 1000).
+type Buckle struct {
+	Solver         string
+	Number         int     // Number of buckling factors desired (usually 1)
+	Accuracy       float64 // Accuracy desired (default: 0.01).
+	LanczosVectors int     // default: 4 * Number
+	MaxIterations  int     // default: 1000
+}
+
+func (b Buckle) String() string {
+	if b.Number <= 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*BUCKLE")
+	if b.Solver != "" {
+		fmt.Fprintf(&buf, ", SOLVER=%s", b.Solver)
+	}
+	// not given values are written as empty fields
+	fs := []string{fmt.Sprintf("%d", b.Number), "", "", ""}
+	if b.Accuracy != 0 {
+		fs[1] = fmt.Sprintf("%.12e", b.Accuracy)
+	}
+	if b.LanczosVectors != 0 {
+		fs[2] = fmt.Sprintf("%d", b.LanczosVectors)
+	}
+	if b.MaxIterations != 0 {
+		fs[3] = fmt.Sprintf("%d", b.MaxIterations)
+	}
+	for fs[len(fs)-1] == "" {
+		fs = fs[:len(fs)-1]
+	}
+	fmt.Fprintf(&buf, "\n%s\n", strings.Join(fs, ","))
+	return buf.String()
+}
+
 func (s *Step) parseBuckle(block []string) (ok bool, err error) {
 	if !isHeader(block[0], "*BUCKLE") {
 		return false, nil
 	}
-	if len(block) > 2 {
-		err = fmt.Errorf("multiline block: %s", strings.Join(block, "\n"))
-		return
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "SOLVER":
+			s.Buckle.Solver = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid buckle parameter: %s", part)
+			return
+		}
 	}
-	fs := fields(block[1])
-	var i64 int64
-	i64, err = strconv.ParseInt(fs[0], 10, 64)
-	if err != nil {
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid block: %s", strings.Join(block, "\n"))
 		return
 	}
-	s.Buckle.Number = int(i64)
-	if 1 < len(fs) {
-		var acc float64
-		acc, err = parseFloat(fs[1])
+	fs := fields(block[1])
+	for i, f := range fs {
+		if f == "" {
+			continue
+		}
+		switch i {
+		case 0:
+			s.Buckle.Number, err = parseInt(f)
+		case 1:
+			s.Buckle.Accuracy, err = parseFloat(f)
+		case 2:
+			s.Buckle.LanczosVectors, err = parseInt(f)
+		case 3:
+			s.Buckle.MaxIterations, err = parseInt(f)
+		}
 		if err != nil {
 			return
 		}
-		s.Buckle.Accuracy = acc
 	}
 	return true, nil
 }
@@ -1406,15 +2189,24 @@ type File struct {
 	Options []string
 }
 
+// Print is output request of keywords *NODE FILE, *EL FILE, *NODE PRINT,
+// *EL PRINT, *CONTACT FILE, *CONTACT PRINT and *ELEMENT OUTPUT.
 type Print struct {
 	SetName        string
-	Frequency      string
-	Output         string
+	Slave, Master  string // only for *CONTACT PRINT
+	Frequency      int
+	IsFrequency    bool // zero is valid value of FREQUENCY
+	FrequencyF     int
+	IsFrequencyF   bool   // zero is valid value of FREQUENCYF
+	Output         string // 2D or 3D
 	TimePoints     string
-	Total          string
+	Total          string // YES, ONLY or NO
+	Position       string
 	ContactElement bool
+	SectionForces  bool
+	LastIterations bool
 	Global         bool
-	Options        []string
+	Options        []OutputVariable
 }
 
 // Example:
@@ -1459,54 +2251,77 @@ type Print struct {
 // all time points defined by the T1 time points sequence
 func (s *Step) parsePrint(block []string, prefix string, pr *[]Print) (ok bool, err error) {
 	if !isHeader(block[0], prefix) {
-		return false, nil
-	}
-	var np Print
-	for _, s := range strings.Split(block[0], ",")[1:] {
-		s = strings.TrimSpace(s)
-		switch {
-		case strings.HasPrefix(s, "NSET="):
-			s = s[5:]
-			np.SetName = s
-		case strings.HasPrefix(s, "ELSET="):
-			s = s[6:]
-			np.SetName = s
-		case strings.HasPrefix(s, "GLOBAL="):
-			s = s[7:]
-			np.Global = s == "YES"
-		case strings.HasPrefix(s, "TIME POINTS="):
-			s = s[12:]
-			np.TimePoints = s
-		case strings.HasPrefix(s, "FREQUENCY="):
-			s = s[10:]
-			// var i64 int64
-			// i64, err = strconv.ParseInt(s, 10, 64)
-			// if err != nil {
-			// 	return
-			// }
-			np.Frequency = s // int(i64)
-		case strings.HasPrefix(s, "OUTPUT"):
-			index := strings.Index(s, "=")
-			s = strings.TrimSpace(s[index+1:])
-			np.Output = s
+		return false, nil
+	}
+	var np Print
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NSET", "ELSET", "SET":
+			np.SetName = value
+		case "SLAVE":
+			np.Slave = value
+		case "MASTER":
+			np.Master = value
+		case "GLOBAL":
+			np.Global = value == "YES"
+		case "TIME POINTS", "TIMEPOINTS":
+			np.TimePoints = value
+		case "FREQUENCY":
+			np.Frequency, err = parseInt(value)
+			np.IsFrequency = true
+		case "FREQUENCYF":
+			np.FrequencyF, err = parseInt(value)
+			np.IsFrequencyF = true
+		case "OUTPUT":
+			np.Output = value
+		case "TOTALS":
+			np.Total = value
+		case "POSITION":
+			np.Position = value
+		case "CONTACT ELEMENT", "CONTACT ELEMENTS":
+			np.ContactElement = true
+		case "SECTION FORCES":
+			np.SectionForces = true
+		case "LAST ITERATIONS":
+			np.LastIterations = true
+		case "":
+			// do nothing
 		default:
-			err = fmt.Errorf("parsePrint cannot parse: `%s`", s)
+			err = fmt.Errorf("not valid %s parameter: %s",
+				strings.ToLower(prefix[1:]), part)
+		}
+		if err != nil {
 			return
 		}
 	}
-	if len(block) == 2 {
-		np.Options = strings.Fields(strings.Replace(block[1], ",", " ", -1))
+	for _, line := range block[1:] {
+		for _, field := range fields(line) {
+			if field == "" {
+				continue
+			}
+			np.Options = append(np.Options, OutputVariable(field))
+		}
 	}
 	(*pr) = append((*pr), np)
 
 	return true, nil
 }
 
-// [*STATIC 0.01,1]
+// Static
+//
+// Examples:
+//
+//	*STATIC
+//	0.01,1
+//
+//	*STATIC,DIRECT
+//	0.1,1.,1e-5,0.2
 //
 // First line:
-// • *STATIC
-// • Enter any needed parameters and their values.
+//   - *STATIC
+//   - Enter any needed parameters and their values:
+//     DIRECT, SOLVER, TIME RESET, TOTAL TIME AT START.
 //
 // Second line (only relevant for nonlinear analyses; for linear analyses, the step
 // length is always 1)
@@ -1514,53 +2329,216 @@ func (s *Step) parsePrint(block []string, prefix string, pr *[]Print) (ok bool,
 //     crementation, unless the parameter DIRECT was specified (default 1.).
 //   - Time period of the step (default 1.).
 //   - Minimum time increment allowed. Only active if DIRECT is not specified.
-//
-// Default is the initial time increment or 1.e-5 times the time period of the
-// step, whichever is smaller.
+//     Default is the initial time increment or 1.e-5 times the time period
+//     of the step, whichever is smaller.
 //   - Maximum time increment allowed. Only active if DIRECT is not specified.
 //     Default is 1.e+30
 //   - Initial time increment for CFD applications (default 1.e-2)
-func (s *Step) parseStatic(block []string) (ok bool, err error) {
-	if !isHeader(block[0], "*STATIC") {
-		return false, nil
+//
+// Zero value of field means value is not given. Use method Defaults for
+// values with CalculiX defaults.
+type Static struct {
+	Direct           bool
+	Solver           string
+	TimeReset        bool
+	TotalTimeAtStart float64
+
+	TimeInc    float64
+	TimePeriod float64
+	MinInc     float64
+	MaxInc     float64
+	CfdInc     float64
+}
+
+// Defaults return static procedure with CalculiX defaults instead of
+// not given values.
+func (st Static) Defaults() Static {
+	if st.TimeInc == 0 {
+		st.TimeInc = 1.0
 	}
-	s.IsStatic = true
-	if len(block) == 1 {
-		return true, nil
+	if st.TimePeriod == 0 {
+		st.TimePeriod = 1.0
 	}
-	if len(block) != 2 {
-		err = fmt.Errorf("not valid: %s", strings.Join(block, "\n"))
-		return
+	if st.MinInc == 0 {
+		st.MinInc = math.Min(st.TimeInc, 1e-5*st.TimePeriod)
+	}
+	if st.MaxInc == 0 {
+		st.MaxInc = 1e30
 	}
-	fields := strings.Fields(strings.Replace(block[1], ",", " ", -1))
-	if len(fields) != 2 {
-		panic(block)
+	if st.CfdInc == 0 {
+		st.CfdInc = 1e-2
 	}
+	return st
+}
 
-	s.Static.TimeInc, err = parseFloat(fields[0])
-	if err != nil {
-		err = fmt.Errorf("%v : %v", block, err)
-		return
+func (st Static) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*STATIC")
+	if st.Direct {
+		fmt.Fprintf(&buf, ", DIRECT")
 	}
+	if st.Solver != "" {
+		fmt.Fprintf(&buf, ", SOLVER=%s", st.Solver)
+	}
+	if st.TimeReset {
+		fmt.Fprintf(&buf, ", TIME RESET")
+	}
+	if st.TotalTimeAtStart != 0 {
+		fmt.Fprintf(&buf, ", TOTAL TIME AT START=%s", efmt.Sprint(st.TotalTimeAtStart))
+	}
+	fmt.Fprintf(&buf, "\n")
+	if line := givenList(st.TimeInc, st.TimePeriod, st.MinInc, st.MaxInc, st.CfdInc); line != "" {
+		fmt.Fprintf(&buf, "%s\n", line)
+	}
+	return buf.String()
+}
 
-	s.Static.TimePeriod, err = parseFloat(fields[1])
-	if err != nil {
-		err = fmt.Errorf("%v : %v", block, err)
+func (s *Step) parseStatic(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*STATIC") {
+		return false, nil
+	}
+	var st Static
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "DIRECT":
+			st.Direct = true
+		case "SOLVER":
+			st.Solver = value
+		case "TIME RESET":
+			st.TimeReset = true
+		case "TOTAL TIME AT START":
+			st.TotalTimeAtStart, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid static parameter: %s", part)
+			return
+		}
+	}
+	switch len(block) {
+	case 1:
+		// default time increments
+	case 2:
+		var vs []float64
+		vs, err = parseFloats(block[1])
+		if err != nil {
+			err = fmt.Errorf("%v : %v", block, err)
+			return
+		}
+		for 0 < len(vs) && vs[len(vs)-1] == 0 {
+			vs = vs[:len(vs)-1]
+		}
+		if 5 < len(vs) {
+			err = fmt.Errorf("too many values of static: %s", block[1])
+			return
+		}
+		vs = append(vs, 0, 0, 0, 0, 0)
+		st.TimeInc, st.TimePeriod, st.MinInc, st.MaxInc, st.CfdInc =
+			vs[0], vs[1], vs[2], vs[3], vs[4]
+	default:
+		err = fmt.Errorf("not valid: %s", strings.Join(block, "\n"))
 		return
 	}
-
+	s.IsStatic = true
+	s.Static = st
 	return true, nil
 }
 
+// Cload is concentrated load.
+//
+// Examples:
+//
+//	*CLOAD
+//	5, 1, 5000.0
+//
+//	*CLOAD,AMPLITUDE=A1,TIME DELAY=1.
+//	LOAD,3,-3.3112583E+00
+//
+// First line:
+//
+//	*CLOAD
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, USER, LOAD CASE, SECTOR, SUBMODEL,
+//	STEP, DATA SET, OMEGA0.
+//
+// Following line:
+//
+//	Node number or node set label.
+//	Degree of freedom.
+//	Magnitude of the load.
 type Cload struct {
 	Position  string
 	Direction int
 	Value     float64
+
+	CloadOptions
+
+	block int // sequence number of source block
+}
+
+// CloadOptions is parameters of *CLOAD block
+type CloadOptions struct {
+	LoadOptions
+	LoadCase int
+	Sector   int
+	Submodel bool
+	Step     int // step of global model, only for SUBMODEL
+	DataSet  int // data set of global model, only for SUBMODEL
+	Omega0   float64
+}
+
+func (o CloadOptions) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s", o.LoadOptions)
+	if o.LoadCase != 0 {
+		fmt.Fprintf(&buf, ", LOAD CASE=%d", o.LoadCase)
+	}
+	if o.Sector != 0 {
+		fmt.Fprintf(&buf, ", SECTOR=%d", o.Sector)
+	}
+	if o.Submodel {
+		fmt.Fprintf(&buf, ", SUBMODEL")
+	}
+	if o.Step != 0 {
+		fmt.Fprintf(&buf, ", STEP=%d", o.Step)
+	}
+	if o.DataSet != 0 {
+		fmt.Fprintf(&buf, ", DATA SET=%d", o.DataSet)
+	}
+	if o.Omega0 != 0 {
+		fmt.Fprintf(&buf, ", OMEGA0=%s", efmt.Sprint(o.Omega0))
+	}
+	return buf.String()
+}
+
+func (load Cload) line() string {
+	return fmt.Sprintf("%s, %d, %s", load.Position, load.Direction, efmt.Sprint(load.Value))
 }
 
 func (load Cload) String() string {
-	return fmt.Sprintf("*CLOAD\n%s, %3d, %.8e\n",
-		load.Position, load.Direction, load.Value)
+	if load.Position == "" {
+		return fmt.Sprintf("*CLOAD%s\n", load.CloadOptions)
+	}
+	return fmt.Sprintf("*CLOAD%s\n%s\n", load.CloadOptions, load.line())
+}
+
+// writeCloads write loads. Rows with same parameters and of the same
+// block are written in one *CLOAD block. Load without position is
+// written as block without rows.
+func writeCloads(buf *bytes.Buffer, loads []Cload) {
+	for i, load := range loads {
+		if i == 0 || load.CloadOptions != loads[i-1].CloadOptions ||
+			load.block != loads[i-1].block || loads[i-1].Position == "" {
+			fmt.Fprintf(buf, "*CLOAD%s\n", load.CloadOptions)
+		}
+		if load.Position != "" {
+			fmt.Fprintf(buf, "%s\n", load.line())
+		}
+	}
 }
 
 // [*CLOAD 5, 1, 5000.0]
@@ -1570,40 +2548,248 @@ func (s *Step) parseCload(block []string) (ok bool, err error) {
 	if !isHeader(block[0], "*CLOAD") {
 		return false, nil
 	}
-	for _, line := range block[1:] {
-		line = strings.Replace(line, ",", " ", -1)
-		fields := strings.Fields(line)
-		if len(fields) != 3 {
-			panic(line)
+	var o CloadOptions
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = o.LoadOptions.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		switch key {
+		case "LOAD CASE":
+			o.LoadCase, err = parseInt(value)
+		case "SECTOR":
+			o.Sector, err = parseInt(value)
+		case "SUBMODEL":
+			o.Submodel = true
+		case "STEP":
+			o.Step, err = parseInt(value)
+		case "DATA SET":
+			o.DataSet, err = parseInt(value)
+		case "OMEGA0":
+			o.Omega0, err = parseFloat(value)
+		default:
+			err = fmt.Errorf("not valid cload parameter: %s", part)
 		}
-		var l Cload
-		l.Position = fields[0]
-
-		var i64 int64
-		i64, err = strconv.ParseInt(fields[1], 10, 64)
 		if err != nil {
-			return
+			return false, err
 		}
-		l.Direction = int(i64)
-
-		l.Value, err = parseFloat(fields[2])
+	}
+	var number int
+	if n := len(s.Cloads); 0 < n {
+		number = s.Cloads[n-1].block + 1
+	}
+	// block without rows is acceptable, for example
+	// *CLOAD,OP=NEW for removing all loads
+	if len(block) == 1 {
+		s.Cloads = append(s.Cloads, Cload{CloadOptions: o, block: number})
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			return false, fmt.Errorf("not valid cload line: %s", line)
+		}
+		l := Cload{Position: fs[0], CloadOptions: o, block: number}
+		l.Direction, err = parseInt(fs[1])
 		if err != nil {
 			return
 		}
-
+		if 2 < len(fs) && fs[2] != "" {
+			l.Value, err = parseFloat(fs[2])
+			if err != nil {
+				return
+			}
+		}
 		s.Cloads = append(s.Cloads, l)
 	}
 
 	return true, nil
 }
 
+// EffectiveCloads return concentrated loads acting in step with node
+// numbers instead of node set names. Loads are sorted by node and
+// degree of freedom.
+//
+// Loads of previous steps are carried over, a load at the same node and
+// degree of freedom replaces the previous one. Parameter OP=NEW removes
+// all previously applied loads.
+// Step must be one of steps of model, loads of steps from the first
+// step up to this step are applied in order.
+func (s *Step) EffectiveCloads(f *Model) (loads []Cload, err error) {
+	stepIndex := -1
+	for i := range f.Steps {
+		if &f.Steps[i] == s {
+			stepIndex = i
+			break
+		}
+	}
+	if stepIndex < 0 {
+		err = fmt.Errorf("step is not found in model")
+		return
+	}
+	type key struct{ node, dof int }
+	active := map[key]Cload{}
+	for _, step := range f.Steps[:stepIndex+1] {
+		for i, load := range step.Cloads {
+			newBlock := i == 0 || load.CloadOptions != step.Cloads[i-1].CloadOptions ||
+				load.block != step.Cloads[i-1].block || step.Cloads[i-1].Position == ""
+			if newBlock && load.Op == "NEW" {
+				active = map[key]Cload{}
+			}
+			if load.Position == "" {
+				continue
+			}
+			var nodes []int
+			nodes, err = f.nodeLocation(load.Position)
+			if err != nil {
+				return
+			}
+			for _, n := range nodes {
+				l := load
+				l.Position = strconv.Itoa(n)
+				active[key{node: n, dof: load.Direction}] = l
+			}
+		}
+	}
+	keys := make([]key, 0, len(active))
+	for k := range active {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].node != keys[j].node {
+			return keys[i].node < keys[j].node
+		}
+		return keys[i].dof < keys[j].dof
+	})
+	for _, k := range keys {
+		loads = append(loads, active[k])
+	}
+	return
+}
+
+// Dload is distributed load.
+//
+// Examples:
+//
+//	*DLOAD
+//	EALL,GRAV,9.81,0.,0.,-1.
+//
+//	*DLOAD,AMPLITUDE=A1
+//	3,P2,0.01
+//	4,P2,0.01
+//
+//	*DLOAD
+//	EALL,CENTRIF,1.E5,0.,0.,0.,0.,0.,1.
+//
+// First line:
+//
+//	*DLOAD
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, USER, LOAD CASE, SECTOR, SECTION.
+//	Block *DLOAD,OP=NEW without following lines removes all
+//	distributed loads.
+//
+// Following line:
+//
+//	Element number or element set label.
+//	Distributed load type label.
+//	Actual magnitude of the load.
+//	Depending on the label: coordinates of a point on the rotation axis
+//	(CENTRIF) and direction of the load (GRAV) or of the rotation
+//	axis (CENTRIF).
+//
+// Load type labels:
+//
+//	P1-P6   - pressure on face of element (suffix NU for user subroutine)
+//	P       - pressure on element
+//	EDNOR1-EDNOR4 - edge load on shell elements
+//	GRAV    - gravity loading
+//	CENTRIF - centrifugal loading
+//	NEWTON  - generalized gravity
 type Dload struct {
-	Values []string
+	Element   string
+	Label     string
+	Magnitude float64
+	Point     [3]float64 // point on rotation axis, only for CENTRIF
+	Direction [3]float64 // direction of GRAV or rotation axis of CENTRIF
+
+	DloadOptions
+
+	block int // sequence number of source block
+}
+
+// DloadOptions is parameters of *DLOAD block
+type DloadOptions struct {
+	LoadOptions
+	LoadCase int
+	Sector   int
+	Section  string
+}
+
+func (o DloadOptions) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s", o.LoadOptions)
+	if o.LoadCase != 0 {
+		fmt.Fprintf(&buf, ", LOAD CASE=%d", o.LoadCase)
+	}
+	if o.Sector != 0 {
+		fmt.Fprintf(&buf, ", SECTOR=%d", o.Sector)
+	}
+	if o.Section != "" {
+		fmt.Fprintf(&buf, ", SECTION=%s", o.Section)
+	}
+	return buf.String()
+}
+
+// Face return face number of pressure load or zero for other loads
+func (load Dload) Face() int {
+	if !strings.HasPrefix(load.Label, "P") {
+		return 0
+	}
+	return faceNumber(strings.TrimPrefix(load.Label, "P"))
+}
+
+func (load Dload) line() string {
+	line := fmt.Sprintf("%s, %s", load.Element, load.Label)
+	switch load.Label {
+	case "NEWTON":
+	case "GRAV":
+		line += ", " + efmt.Sprint(load.Magnitude)
+		for _, v := range load.Direction {
+			line += ", " + efmt.Sprint(v)
+		}
+	case "CENTRIF":
+		line += ", " + efmt.Sprint(load.Magnitude)
+		for _, v := range load.Point {
+			line += ", " + efmt.Sprint(v)
+		}
+		for _, v := range load.Direction {
+			line += ", " + efmt.Sprint(v)
+		}
+	default:
+		line += ", " + efmt.Sprint(load.Magnitude)
+	}
+	return line
 }
 
 func (load Dload) String() string {
-	return fmt.Sprintf("*DLOAD\n%s\n",
-		strings.Join(load.Values, " ,"))
+	return fmt.Sprintf("*DLOAD%s\n%s\n", load.DloadOptions, load.line())
+}
+
+// writeDloads write loads. Rows with same parameters and of the same
+// block are written in one *DLOAD block. Load without element is
+// written as block without rows.
+func writeDloads(buf *bytes.Buffer, loads []Dload) {
+	for i, load := range loads {
+		if i == 0 || load.DloadOptions != loads[i-1].DloadOptions ||
+			load.block != loads[i-1].block || loads[i-1].Element == "" {
+			fmt.Fprintf(buf, "*DLOAD%s\n", load.DloadOptions)
+		}
+		if load.Element != "" {
+			fmt.Fprintf(buf, "%s\n", load.line())
+		}
+	}
 }
 
 // [*DLOAD EALL,GRAV,9.81,0.,0.,-1.]
@@ -1613,56 +2799,115 @@ func (s *Step) parseDload(block []string) (ok bool, err error) {
 	if !isHeader(block[0], "*DLOAD") {
 		return false, nil
 	}
-	if len(block) != 2 {
-		return false, fmt.Errorf("not valid Dload")
+	var o DloadOptions
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = o.LoadOptions.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		switch key {
+		case "LOAD CASE":
+			o.LoadCase, err = parseInt(value)
+		case "SECTOR":
+			o.Sector, err = parseInt(value)
+		case "SECTION":
+			o.Section = value
+		default:
+			err = fmt.Errorf("not valid dload parameter: %s", part)
+		}
+		if err != nil {
+			return false, err
+		}
 	}
-	var load Dload
-	load.Values = fields(block[1])
-	s.Dloads = append(s.Dloads, load)
-	return true, nil
-}
-
-func (f *Model) parseTimePoint(block []string) (ok bool, err error) {
-	if !isHeader(block[0], "*TIME POINTS") {
-		return false, nil
+	var number int
+	if n := len(s.Dloads); 0 < n {
+		number = s.Dloads[n-1].block + 1
 	}
-
-	for _, s := range strings.Split(block[0], ",")[1:] {
-		s = strings.TrimSpace(s)
-		switch {
-		case strings.HasPrefix(s, "NAME="):
-			s = s[5:]
-			f.TimePoint.Name = s
-		case strings.HasPrefix(s, "GENERATE"):
-			f.TimePoint.Generate = true
-		default:
-			panic(s)
+	if len(block) == 1 {
+		if o.Op != "NEW" {
+			return false, fmt.Errorf("not valid Dload")
+		}
+		// block without rows removes all distributed loads
+		s.Dloads = append(s.Dloads, Dload{DloadOptions: o, block: number})
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			return false, fmt.Errorf("not valid Dload line: %s", line)
+		}
+		load := Dload{
+			Element:      fs[0],
+			Label:        strings.ToUpper(fs[1]),
+			DloadOptions: o,
+			block:        number,
+		}
+		var vs []float64
+		vs, err = parseFloats(strings.Join(fs[2:], ","))
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0, 0, 0, 0, 0, 0)
+		load.Magnitude = vs[0]
+		switch load.Label {
+		case "GRAV":
+			copy(load.Direction[:], vs[1:4])
+		case "CENTRIF":
+			copy(load.Point[:], vs[1:4])
+			copy(load.Direction[:], vs[4:7])
 		}
+		s.Dloads = append(s.Dloads, load)
 	}
+	return true, nil
+}
 
-	line := strings.Replace(block[1], ",", " ", -1)
-	fields := strings.Fields(line)
-	var t float64
+// faceAmount return amount of faces of element type for pressure loads.
+// Zero is returned for element types without face numbering.
+func faceAmount(elType string) int {
+	switch {
+	case strings.HasPrefix(elType, "C3D"):
+		switch cornerNodes(elType) {
+		case 8:
+			return 6
+		case 6:
+			return 5
+		case 4:
+			return 4
+		}
+	case strings.HasPrefix(elType, "S"), strings.HasPrefix(elType, "M3D"):
+		// faces in normal directions and edges
+		return 2 + cornerNodes(elType)
+	case strings.HasPrefix(elType, "CPS"), strings.HasPrefix(elType, "CPE"),
+		strings.HasPrefix(elType, "CAX"):
+		return cornerNodes(elType)
+	}
+	return 0
+}
 
-	t, err = parseFloat(fields[0])
-	if err != nil {
-		return
+// CheckDload return error if face number of load label is not valid
+// for type of any loaded element.
+func (f Model) CheckDload(load Dload) (err error) {
+	face := load.Face()
+	if face == 0 {
+		return nil
 	}
-	f.TimePoint.Time = append(f.TimePoint.Time, t)
-
-	t, err = parseFloat(fields[1])
+	indexes, err := f.elementLocation(load.Element)
 	if err != nil {
 		return
 	}
-	f.TimePoint.Time = append(f.TimePoint.Time, t)
-
-	t, err = parseFloat(fields[2])
-	if err != nil {
-		return
+	elements := f.elementByIndex()
+	for _, index := range indexes {
+		el, ok := elements[index]
+		if !ok {
+			return fmt.Errorf("dload %s: not found element %d", load.Label, index)
+		}
+		if amount := faceAmount(el.Type); 0 < amount && amount < face {
+			return fmt.Errorf("dload %s: element %d of type %s has only %d faces",
+				load.Label, index, el.Type, amount)
+		}
 	}
-	f.TimePoint.Time = append(f.TimePoint.Time, t)
-
-	return true, nil
+	return nil
 }
 
 func (f *Model) parsePlastic(block []string) (ok bool, err error) {
@@ -1670,9 +2915,7 @@ func (f *Model) parsePlastic(block []string) (ok bool, err error) {
 		return false, nil
 	}
 
-	if len(f.Materials) == 0 {
-		f.Materials = make([]Material, 1)
-	}
+	m := f.lastMaterial()
 
 	for _, s := range strings.Split(block[0], ",")[1:] {
 		s = strings.TrimSpace(s)
@@ -1680,7 +2923,7 @@ func (f *Model) parsePlastic(block []string) (ok bool, err error) {
 		switch {
 		case strings.HasPrefix(s, prefixH):
 			s = s[len(prefixH):]
-			f.Materials[0].Plastic.Hardening = s
+			m.Plastic.Hardening = s
 		default:
 			panic(s)
 		}
@@ -1690,18 +2933,18 @@ func (f *Model) parsePlastic(block []string) (ok bool, err error) {
 		line = strings.Replace(line, ",", " ", -1)
 		fields := strings.Fields(line)
 
-		f.Materials[0].Plastic.Data[pos].StressVonMises, err = parseFloat(fields[0])
+		m.Plastic.Data[pos].StressVonMises, err = parseFloat(fields[0])
 		if err != nil {
 			return
 		}
-		f.Materials[0].Plastic.Data[pos].PlasticStrain, err = parseFloat(fields[1])
+		m.Plastic.Data[pos].PlasticStrain, err = parseFloat(fields[1])
 		if err != nil {
 			return
 		}
 		if len(fields) == 2 {
 			continue
 		}
-		f.Materials[0].Plastic.Data[pos].Temperature, err = parseFloat(fields[2])
+		m.Plastic.Data[pos].Temperature, err = parseFloat(fields[2])
 		if err != nil {
 			return
 		}
@@ -1777,10 +3020,11 @@ func splitByBlocks(lines []string) (blocks [][]string) {
 
 func Parse(content []byte) (f *Model, err error) {
 	// split into lines
-	var lines []string
+	var raw, lines []string
 	{
 		dat := string(content)
 		dat = strings.ReplaceAll(dat, "\r", "")
+		raw = strings.Split(dat, "\n")
 		dat = strings.ToUpper(dat)
 		dat = strings.ReplaceAll(dat, "  ", " ")
 		lines = strings.Split(dat, "\n")
@@ -1805,11 +3049,20 @@ func Parse(content []byte) (f *Model, err error) {
 		}
 	}
 
+	chunks, comments, tail := sourceChunks(raw, lines)
+
 	// parsing
 	f = new(Model)
+	groups := f.itemGroups(WriteOptions{})
 
 	et := errors.New("Parse")
-	for _, block := range blocks {
+	for i, block := range blocks {
+		var prev []Range // ranges of previous keyword
+		if n := len(f.Source); 0 < n {
+			prev = f.Source[n-1].Ranges
+		}
+		state := newBlockState(groups, prev)
+		var steps []blockRanges // ranges of keywords inside of step
 		err := blockParser(block, []func(block []string) (ok bool, err error){
 			f.parseNode,
 			f.parseHeading,
@@ -1825,25 +3078,66 @@ func Parse(content []byte) (f *Model, err error) {
 			f.parseElastic,
 			parseBoundary(&f.Boundaries),
 			f.parseMaterial,
-			ignore("*SURFACE"),
+			f.parseSurface,
 			f.parseBeamSection,
 			f.parseSolidSection,
 			f.parseShellSection,
-			f.parseStep,
+			func(block []string) (ok bool, err error) {
+				ok, steps, err = f.parseStep(block)
+				return
+			},
 			f.parsePlastic,
+			f.parseDamping,
+			f.parseConductivity,
+			f.parseInitialConditions,
+			f.parseSpecificHeat,
 			f.parseTimePoint,
+			f.parseContactPair,
+			f.parseSurfaceInteraction,
+			f.parseSurfaceBehavior,
+			f.parseFriction,
+			f.parseGap,
+			f.parseTie,
+			f.parseOrientation,
+			f.parseTransform,
+			f.parseCyclicSymmetryModel,
+			f.parseFluidSection,
+			f.parseFluidConstants,
+			f.parseSpecificGasConstant,
+			f.parseDesignVariables,
+			f.parseNodalThickness,
+			f.parseNormal,
+			f.parseMass,
+			f.parseDashpot,
+			f.parseRotaryInertia,
+			f.parseCoupling,
+			f.parseCouplingType,
+			f.parseDistributingCoupling,
+			f.parseSubmodel,
+			f.parsePhysicalConstants,
+			parseRestart(&f.Restart),
 			// ignore("*END STEP"),
-			// ignore("*HEAT TRANSFER"),
-			// ignore("*CONDUCTIVITY"),
-			// ignore("*FLUID"),
-			// ignore("*SPECIFIC GAS CONSTANT"),
-			// ignore("*SPECIFIC HEAT"),
-			// ignore("*PHYSICAL CONSTANTS"),
 		})
 		if err != nil {
 			_ = et.Add(err)
+		} else if 0 < len(block) && isHeader(block[0], "*SUBMODEL") {
+			// name of results file is case sensitive
+			s := &f.Submodels[len(f.Submodels)-1]
+			s.Input = rawValue(chunks[i][comments[i]], "INPUT", s.Input)
+		}
+		r := state.changed(groups)
+		// blocks inside of step are part of step
+		r.part = r.part || len(block) == 0
+		f.addKeyword(chunks[i], comments[i], r)
+		if end := i + len(steps) + 1; steps != nil && end < len(chunks) {
+			f.Source[len(f.Source)-1].Keywords = stepKeywords(
+				chunks[i:end+1], comments[i:end+1], steps)
 		}
 	}
+	if 0 < len(tail) {
+		f.Source = append(f.Source, Keyword{Lines: tail, Comments: len(tail)})
+	}
+	f.sumSource()
 	if et.IsError() {
 		err = et
 	}
@@ -3001,6 +4295,72 @@ func parseFloat(str string) (v float64, err error) {
 	return
 }
 
+// keyValue split parameter of keyword line into key and value.
+//
+// Example:
+//
+//	"NAME = SI1" return "NAME" and "SI1"
+//	"NLGEOM"     return "NLGEOM" and ""
+func keyValue(str string) (key, value string) {
+	index := strings.Index(str, "=")
+	if index < 0 {
+		return strings.TrimSpace(str), ""
+	}
+	return strings.TrimSpace(str[:index]), strings.TrimSpace(str[index+1:])
+}
+
+// rawValue return value of parameter key of keyword line with original
+// case of letters. Value def is returned, if parameter is not found.
+func rawValue(line, key, def string) string {
+	for _, part := range fields(line)[1:] {
+		k, v := keyValue(part)
+		if strings.EqualFold(strings.Join(strings.Fields(k), " "), key) {
+			return v
+		}
+	}
+	return def
+}
+
+// parseFloats parse all fields of data line.
+// Empty field is acceptable and parsed as zero.
+func parseFloats(line string) (vs []float64, err error) {
+	for _, s := range fields(line) {
+		var v float64
+		if s != "" {
+			v, err = parseFloat(s)
+			if err != nil {
+				return
+			}
+		}
+		vs = append(vs, v)
+	}
+	return
+}
+
+// floatList return values separated by comma.
+func floatList(vs ...float64) string {
+	list := make([]string, len(vs))
+	for i, v := range vs {
+		list[i] = efmt.Sprint(v)
+	}
+	return strings.Join(list, ", ")
+}
+
+// givenList return values separated by comma. Zero values are not
+// given and written as empty fields, trailing empty fields are omitted.
+func givenList(vs ...float64) string {
+	for 0 < len(vs) && vs[len(vs)-1] == 0 {
+		vs = vs[:len(vs)-1]
+	}
+	list := make([]string, len(vs))
+	for i, v := range vs {
+		if v != 0 {
+			list[i] = efmt.Sprint(v)
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
 type Dat struct {
 	BucklingFactors    []float64
 	Temperatures       []Single
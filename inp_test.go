@@ -1,8 +1,12 @@
 package inp_test
 
 import (
+	"bytes"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/Konstantin8105/inp"
@@ -143,3 +147,1713 @@ func Benchmark(b *testing.B) {
 		}
 	}
 }
+
+// roundTrip parse content, write and parse again.
+// Both outputs must be same.
+func roundTrip(t *testing.T, content string) *inp.Model {
+	t.Helper()
+	format, err := inp.Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f1 := format.String()
+	format2, err := inp.Parse([]byte(f1))
+	if err != nil {
+		t.Fatalf("%v\n%s", err, f1)
+	}
+	if f2 := format2.String(); f1 != f2 {
+		t.Fatalf("not same:\n%s\n%s", f1, f2)
+	}
+	return format
+}
+
+func TestContact(t *testing.T) {
+	f := roundTrip(t, `
+*NODE
+1, 0, 0, 0
+*SURFACE, NAME=SMAST
+34, S2
+*SURFACE, NAME=SSLAV, TYPE=NODE
+1
+*CONTACT PAIR,INTERACTION=SI1,SMALL SLIDING,ADJUST=0.005,TYPE=SURFACE TO SURFACE
+SSLAV,SMAST
+*SURFACE INTERACTION,NAME=SI1
+*SURFACE BEHAVIOR,PRESSURE-OVERCLOSURE=LINEAR
+1.E7,3.
+*FRICTION
+0.2,1.E5
+*GAP,ELSET=G1
+0.,0.,0.,1.,,1.E5,0.1
+`)
+	if len(f.ContactPairs) != 1 || len(f.SurfaceInteractions) != 1 || len(f.Gaps) != 1 {
+		t.Fatalf("not valid amount of contact definitions")
+	}
+	cp := f.ContactPairs[0]
+	if cp.Type != inp.SurfaceToSurface || !cp.SmallSliding || cp.Adjust != "0.005" {
+		t.Errorf("not valid contact pair: %#v", cp)
+	}
+	slave, master, err := f.ContactSurfaces(cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slave.IsElementType || !master.IsElementType {
+		t.Errorf("not valid surface types")
+	}
+	si, err := f.ContactInteraction(cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if si.Behavior.K != 1e7 || si.Behavior.SigmaInf != 3 || si.Friction.Mu != 0.2 {
+		t.Errorf("not valid interaction: %#v", si)
+	}
+	if g := f.Gaps[0]; g.Direction[2] != 1 || g.K != 1e5 || g.Finf != 0.1 {
+		t.Errorf("not valid gap: %#v", g)
+	}
+	if out := slave.String(); !strings.Contains(out, "TYPE=NODE\n1\n") {
+		t.Errorf("not valid node surface:\n%s", out)
+	}
+	sb := inp.SurfaceBehavior{PressureOverclosure: inp.Exponential}
+	if out := sb.String(); out != "*SURFACE BEHAVIOR, PRESSURE-OVERCLOSURE=EXPONENTIAL\n" {
+		t.Errorf("data line must not be written:\n%s", out)
+	}
+	// all-zero rows must be kept
+	if out := (inp.Friction{StickSlope: 1e5}).String(); out != "*FRICTION\n0.00000, 100000.\n" {
+		t.Errorf("not valid friction:\n%s", out)
+	}
+	g := inp.Gap{Elset: "G2", K: 1e5}
+	if out := g.String(); !strings.HasSuffix(out, "\n0.00000, 0.00000, 0.00000, 0.00000, 0.00000, 100000., 0.00000\n") {
+		t.Errorf("not valid gap:\n%s", out)
+	}
+	g = roundTrip(t, "*GAP,ELSET=G3\n0.,0.,0.,0.,,0.,0.\n").Gaps[0]
+	if g.Elset != "G3" || g.Clearance != 0 || g.K != 0 {
+		t.Errorf("not valid all-zero gap: %#v", g)
+	}
+}
+
+func TestTie(t *testing.T) {
+	f := roundTrip(t, `
+*NODE
+1, 0, 0, 0
+2, 1, 0, 0
+3, 1, 1, 0
+4, 0, 1, 0
+5, 0, 0, 1
+6, 1, 0, 1
+7, 1, 1, 1
+8, 0, 1, 1
+10, 0.5, 0.5, 1.01
+11, 0.5, 0.5, 1.5
+*ELEMENT, TYPE=C3D8, ELSET=EALL
+1, 1, 2, 3, 4, 5, 6, 7, 8
+*NSET, NSET=NDEP
+10, 11
+*SURFACE, NAME=SDEP, TYPE=NODE
+NDEP
+*SURFACE, NAME=SIND
+EALL, S2
+*TIE,NAME=T1,ADJUST=NO,POSITION TOLERANCE=0.15
+SDEP,SIND
+`)
+	if len(f.Ties) != 1 {
+		t.Fatalf("not valid amount of ties")
+	}
+	tie := f.Ties[0]
+	if tie.Name != "T1" || tie.Adjust != "NO" || tie.PositionTolerance != 0.15 {
+		t.Errorf("not valid tie: %#v", tie)
+	}
+	nodes, err := f.TieOutOfTolerance(tie)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0] != 11 {
+		t.Errorf("not valid nodes out of tolerance: %v", nodes)
+	}
+}
+
+func TestOrientation(t *testing.T) {
+	f := roundTrip(t, `
+*NODE
+1, 1, 0, 0
+2, 0, 2, 5
+*ELEMENT, TYPE=C3D4, ELSET=EALL
+1, 1, 2, 1, 2
+*NSET, NSET=N1
+2
+*ORIENTATION,NAME=OR1,SYSTEM=RECTANGULAR
+0.,1.,0.,-1.,0.,0.
+*ORIENTATION,NAME=OR2,SYSTEM=CYLINDRICAL
+0.,0.,0.,0.,0.,1.
+3, 90.
+*TRANSFORM,NSET=N1,TYPE=C
+0.,0.,0.,0.,0.,1.
+*SOLID SECTION,ELSET=EALL,MATERIAL=EL,ORIENTATION=OR1
+`)
+	if len(f.Orientations) != 2 || len(f.Transforms) != 1 {
+		t.Fatalf("not valid amount of orientations")
+	}
+	if f.SolidSections[0].Orientation != "OR1" {
+		t.Errorf("not valid solid section orientation")
+	}
+	eps := 1e-12
+	r, err := f.Orientations[1].Rotation([3]float64{0, 3, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// radial direction is global Y, after rotation about local z
+	// local x is global -X
+	if math.Abs(r[0][0]+1) > eps || math.Abs(r[1][1]+1) > eps || math.Abs(r[2][2]-1) > eps {
+		t.Errorf("not valid rotation: %v", r)
+	}
+	forces, err := f.GlobalCload(inp.Cload{Position: "N1", Direction: 1, Value: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := forces[2]; math.Abs(v[0]) > eps || math.Abs(v[1]-10) > eps {
+		t.Errorf("not valid global load: %v", v)
+	}
+	s, err := f.LocalStress(inp.Stress{Node: 1, Values: [6]float64{100, 0, 0, 0, 0, 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(s.Values[1]-100) > eps || math.Abs(s.Values[0]) > eps {
+		t.Errorf("not valid local stress: %v", s.Values)
+	}
+}
+
+func TestEigenProcedures(t *testing.T) {
+	f := roundTrip(t, `
+*STEP
+*FREQUENCY,SOLVER=ARPACK,STORAGE=YES
+10,0.01,1000.
+*END STEP
+*STEP
+*BUCKLE,SOLVER=SPOOLES
+5,1.E-3,30,500
+*END STEP
+`)
+	if len(f.Steps) != 2 {
+		t.Fatalf("not valid amount of steps")
+	}
+	fr := f.Steps[0].Frequency
+	if fr.Solver != "ARPACK" || fr.Storage != "YES" || fr.Number != 10 ||
+		fr.LowerBound != 0.01 || fr.UpperBound != 1000 {
+		t.Errorf("not valid frequency: %#v", fr)
+	}
+	b := f.Steps[1].Buckle
+	if b.Solver != "SPOOLES" || b.Number != 5 || b.Accuracy != 1e-3 ||
+		b.LanczosVectors != 30 || b.MaxIterations != 500 {
+		t.Errorf("not valid buckle: %#v", b)
+	}
+	b = inp.Buckle{Number: 2, MaxIterations: 100}
+	if out := b.String(); out != "*BUCKLE\n2,,,100\n" {
+		t.Errorf("not valid buckle output: %q", out)
+	}
+}
+
+func TestDynamicProcedures(t *testing.T) {
+	f := roundTrip(t, `
+*MATERIAL,NAME=EL
+*DAMPING,ALPHA=500000.,BETA=0.
+*MATERIAL,NAME=EL2
+*DAMPING,STRUCTURAL=0.03
+*STEP
+*DYNAMIC,DIRECT,ALPHA=-0.3
+1.E-8,2.E-7
+*END STEP
+*STEP
+*DYNAMIC,ALPHA=0.
+1.E-8,2.E-7
+*END STEP
+*STEP
+*MODAL DYNAMIC
+1.E-5,1.E-4
+*MODAL DAMPING,RAYLEIGH
+,,5000.,0.
+*END STEP
+*STEP
+*STEADY STATE DYNAMICS,HARMONIC=NO
+12000.,14000.,3,1.,40,0.,0.8333E-4
+*MODAL DAMPING
+1,5,.5
+*END STEP
+`)
+	if len(f.Steps) != 4 {
+		t.Fatalf("not valid amount of steps")
+	}
+	if d := f.Materials[0].Damping; d.Alpha != 5e5 {
+		t.Errorf("not valid material damping: %#v", d)
+	}
+	if d := f.Materials[1].Damping; d.Structural != 0.03 || d.Alpha != 0 {
+		t.Errorf("not valid material damping: %#v", d)
+	}
+	if d := f.Steps[1].Dynamic; !d.IsAlpha || !strings.Contains(d.String(), "ALPHA=0") {
+		t.Errorf("not valid zero alpha: %#v", d)
+	}
+	f.Steps = append(f.Steps[:1], f.Steps[2:]...)
+	for i, p := range []inp.Procedure{inp.ProcedureDynamic, inp.ProcedureModalDynamic, inp.ProcedureSteadyStateDynamics} {
+		if pr := f.Steps[i].Procedure(); pr != p {
+			t.Errorf("step %d: not valid procedure %s", i, pr)
+		}
+	}
+	if d := f.Steps[0].Dynamic; !d.Direct || d.Alpha != -0.3 || d.TimePeriod != 2e-7 {
+		t.Errorf("not valid dynamic: %#v", d)
+	}
+	if md := f.Steps[1].ModalDamping; !md.Rayleigh || md.Alpha != 5000 {
+		t.Errorf("not valid modal damping: %#v", md)
+	}
+	ssd := f.Steps[2].SteadyStateDynamics
+	if ssd.Harmonic != "NO" || ssd.Points != 3 || ssd.FourierTerms != 40 || ssd.UpperTime != 0.8333e-4 {
+		t.Errorf("not valid steady state dynamics: %#v", ssd)
+	}
+	if md := f.Steps[2].ModalDamping; len(md.Modes) != 1 || md.Modes[0].HighestMode != 5 {
+		t.Errorf("not valid modal damping: %#v", md)
+	}
+	// procedure is keyword of source, but not values of procedure
+	s := inp.Step{Dynamic: inp.Dynamic{TimeInc: 1e-8, TimePeriod: 2e-7}}
+	if pr := s.Procedure(); pr != "" {
+		t.Errorf("not valid procedure of step without keyword: %s", pr)
+	}
+	s.IsDynamic = true
+	if pr := s.Procedure(); pr != inp.ProcedureDynamic {
+		t.Errorf("not valid procedure of step: %s", pr)
+	}
+}
+
+func TestThermal(t *testing.T) {
+	f := roundTrip(t, `
+*STEP
+*HEAT TRANSFER,DELTMX=50.
+1.e-4,.01
+*DFLUX,AMPLITUDE=A1
+20,S3,10.
+LAST,BF,7.8E2
+*CFLUX
+1,11,10.
+*FILM
+20,F3,18.,2.
+1,F1FC,12,10.
+*RADIATE, CAVITY=1
+1021, R4CRNU101,   -1.000, 1.0
+3, R1CR,,1.
+*END STEP
+*STEP
+*COUPLED TEMPERATURE-DISPLACEMENT,STEADY STATE,DIRECT
+.1,1.
+*END STEP
+*STEP
+*HEAT TRANSFER,FREQUENCY,STORAGE=YES
+10
+*END STEP
+`)
+	if len(f.Steps) != 3 {
+		t.Fatalf("not valid amount of steps")
+	}
+	s := f.Steps[0]
+	if s.Procedure() != "*HEAT TRANSFER" || s.HeatTransfer.Deltmx != 50 || s.HeatTransfer.TimePeriod != 0.01 {
+		t.Errorf("not valid heat transfer: %#v", s.HeatTransfer)
+	}
+	if len(s.Dfluxes) != 1 || len(s.Dfluxes[0].Fluxes) != 2 ||
+		s.Dfluxes[0].Amplitude != "A1" || s.Dfluxes[0].Fluxes[0].Face() != 3 ||
+		s.Dfluxes[0].Fluxes[1].Face() != 0 {
+		t.Errorf("not valid dflux: %#v", s.Dfluxes)
+	}
+	if len(s.Cfluxes) != 1 || s.Cfluxes[0].Fluxes[0].Direction != 11 {
+		t.Errorf("not valid cflux: %#v", s.Cfluxes)
+	}
+	if fc := s.Films[0].Conditions; len(fc) != 2 || fc[0].SinkTemperature != 18 ||
+		fc[0].Coefficient != 2 || !fc[1].IsForced() || fc[1].SinkNode != 12 || fc[1].Face() != 1 {
+		t.Errorf("not valid film: %#v", s.Films)
+	}
+	if r := s.Radiates[0]; r.Cavity != "1" || len(r.Conditions) != 2 ||
+		!r.Conditions[0].IsCavity() || r.Conditions[0].Face() != 4 ||
+		r.Conditions[0].SinkTemperature != -1 || r.Conditions[1].Emissivity != 1 {
+		t.Errorf("not valid radiate: %#v", r)
+	}
+	if s := f.Steps[1]; s.Procedure() != "*COUPLED TEMPERATURE-DISPLACEMENT" ||
+		!s.HeatTransfer.SteadyState || !s.HeatTransfer.Direct {
+		t.Errorf("not valid coupled step: %#v", s.HeatTransfer)
+	}
+	if h := f.Steps[2].HeatTransfer; !h.Frequency || h.Number != 10 || h.Storage != "YES" {
+		t.Errorf("not valid heat transfer frequency: %#v", h)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	f := roundTrip(t, `
+*STEP
+*STATIC,DIRECT,SOLVER=PARDISO,TIME RESET,TOTAL TIME AT START=2.
+0.1,1.,1e-5,0.2
+*END STEP
+*STEP
+*STATIC
+*END STEP
+`)
+	st := f.Steps[0].Static
+	if !st.Direct || st.Solver != "PARDISO" || !st.TimeReset || st.TotalTimeAtStart != 2 ||
+		st.TimeInc != 0.1 || st.MinInc != 1e-5 || st.MaxInc != 0.2 || st.CfdInc != 0 {
+		t.Errorf("not valid static: %#v", st)
+	}
+	if d := st.Defaults(); d.CfdInc != 1e-2 || d.MaxInc != 0.2 {
+		t.Errorf("not valid defaults: %#v", d)
+	}
+	st = f.Steps[1].Static
+	if !f.Steps[1].IsStatic || st.TimeInc != 0 {
+		t.Errorf("not valid static: %#v", st)
+	}
+	if d := st.Defaults(); d.TimeInc != 1 || d.TimePeriod != 1 || d.MinInc != 1e-5 || d.MaxInc != 1e30 {
+		t.Errorf("not valid defaults: %#v", d)
+	}
+	if out := st.String(); out != "*STATIC\n" {
+		t.Errorf("data line must not be written:\n%s", out)
+	}
+	st = inp.Static{TimePeriod: 2, MaxInc: 0.5}
+	if out := st.String(); !strings.HasSuffix(out, "\n, 2.00000, , 0.50000\n") {
+		t.Errorf("not given values must be empty:\n%s", out)
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	f := roundTrip(t, `
+*BOUNDARY
+1,1,3
+2,1
+*STEP
+*BOUNDARY,OP=NEW,AMPLITUDE=A1,LOAD CASE=2
+NFIX,3,3,0.1
+NFIX,1,,0.
+*BOUNDARY,FIXED
+N2,1,3
+*BOUNDARY,TYPE=VELOCITY
+N3,ENCASTRE
+N4,xsymm
+*BOUNDARY,SUBMODEL,STEP=1
+N5,1,3
+*BOUNDARY,MASS FLOW
+9,1,1,5.e-7
+*END STEP
+*STEP
+*BOUNDARY,OP=NEW
+*END STEP
+*STEP
+*BOUNDARY,OP=NEW
+*BOUNDARY,OP=NEW
+1,1
+*BOUNDARY,OP=NEW
+2,2
+*END STEP
+`)
+	if len(f.Boundaries) != 2 || f.Boundaries[1].Start != 1 || f.Boundaries[1].Finish != 0 {
+		t.Fatalf("not valid model boundaries: %#v", f.Boundaries)
+	}
+	bs := f.Steps[0].Boundaries
+	if len(bs) != 7 {
+		t.Fatalf("not valid amount of boundaries: %d", len(bs))
+	}
+	if bs[0].Op != "NEW" || bs[0].Amplitude != "A1" || bs[0].LoadCase != 2 ||
+		bs[0].Factor != 0.1 || bs[1].Op != "NEW" {
+		t.Errorf("not valid boundary: %#v", bs[0])
+	}
+	if !bs[2].Fixed || bs[3].Type != "VELOCITY" || bs[3].Named != "ENCASTRE" ||
+		len(bs[3].Dofs()) != 6 || bs[4].Named != "XSYMM" || !bs[5].Submodel ||
+		bs[5].Step != 1 || !bs[6].MassFlow {
+		t.Errorf("not valid boundaries: %#v", bs)
+	}
+	if len(f.Steps[1].Boundaries) != 1 || f.Steps[1].Boundaries[0].Op != "NEW" {
+		t.Errorf("not valid empty boundary: %#v", f.Steps[1].Boundaries)
+	}
+	if n := strings.Count(f.Steps[0].String(), "*BOUNDARY"); n != 5 {
+		t.Errorf("not valid amount of boundary blocks: %d", n)
+	}
+	if n := strings.Count(f.Steps[2].String(), "*BOUNDARY, OP=NEW\n"); n != 3 {
+		t.Errorf("blocks with OP=NEW must not be merged: %d\n%s", n, f.Steps[2])
+	}
+
+	// rows with the same parameters are written in one block
+	o := inp.BoundaryOptions{LoadOptions: inp.LoadOptions{Op: "NEW"}}
+	s := inp.Step{Boundaries: []inp.Boundary{
+		{LoadLocation: "1", Start: 1, BoundaryOptions: o},
+		{LoadLocation: "2", Start: 1, BoundaryOptions: o},
+	}}
+	if n := strings.Count(s.String(), "*BOUNDARY"); n != 1 {
+		t.Errorf("rows with the same parameters must be merged: %d\n%s", n, s)
+	}
+}
+
+func TestDload(t *testing.T) {
+	f := roundTrip(t, `
+*NODE
+1,0,0,0
+2,1,0,0
+3,1,1,0
+4,0,1,0
+5,0,0,1
+*ELEMENT,TYPE=C3D4,ELSET=E1
+1,1,2,4,5
+*ELEMENT,TYPE=S4,ELSET=E2
+2,1,2,3,4
+*STEP
+*DLOAD,AMPLITUDE=A1
+E1,P4,0.01
+2,P6,0.01
+*DLOAD,OP=NEW,LOAD CASE=2,SECTOR=5
+E1,GRAV,9.81,0.,0.,-1.
+E1,CENTRIF,1.E5,0.,0.,0.,0.,0.,1.
+E1,NEWTON
+*END STEP
+*STEP
+*DLOAD,OP=NEW
+*DLOAD,OP=NEW,SECTION=2
+E1,P4,0.02
+*DLOAD,OP=NEW,SECTION=2
+2,P6,0.02
+*END STEP
+`)
+	ls := f.Steps[0].Dloads
+	if len(ls) != 5 {
+		t.Fatalf("not valid amount of loads: %d", len(ls))
+	}
+	if ls[0].Amplitude != "A1" || ls[0].Face() != 4 || ls[1].Magnitude != 0.01 {
+		t.Errorf("not valid pressure: %#v", ls[0])
+	}
+	if ls[2].Op != "NEW" || ls[2].LoadCase != 2 || ls[2].Sector != 5 ||
+		ls[2].Direction != [3]float64{0, 0, -1} || ls[2].Magnitude != 9.81 {
+		t.Errorf("not valid gravity: %#v", ls[2])
+	}
+	if ls[3].Magnitude != 1e5 || ls[3].Direction != [3]float64{0, 0, 1} || ls[4].Label != "NEWTON" {
+		t.Errorf("not valid centrifugal: %#v", ls[3:])
+	}
+	for i := range ls {
+		if err := f.CheckDload(ls[i]); err != nil {
+			t.Errorf("load %d: %v", i, err)
+		}
+	}
+	if err := f.CheckDload(inp.Dload{Element: "E1", Label: "P5"}); err == nil {
+		t.Errorf("face 5 of tetra must be invalid")
+	}
+	if err := f.CheckDload(inp.Dload{Element: "2", Label: "P7"}); err == nil {
+		t.Errorf("face 7 of shell must be invalid")
+	}
+	if n := strings.Count(f.Steps[0].String(), "*DLOAD"); n != 2 {
+		t.Errorf("not valid amount of dload blocks: %d", n)
+	}
+	out := f.Steps[1].String()
+	if n := strings.Count(out, "*DLOAD, OP=NEW"); n != 3 ||
+		strings.Count(out, "SECTION=2") != 2 || strings.Contains(out, "SECTOR") {
+		t.Errorf("blocks with OP=NEW must not be merged:\n%s", out)
+	}
+}
+
+func TestCload(t *testing.T) {
+	f := roundTrip(t, `
+*NODE,NSET=NALL
+1,0,0,0
+2,1,0,0
+3,1,1,0
+*STEP
+*CLOAD,AMPLITUDE=A1,TIME DELAY=1.
+NALL,1,10.
+3,2
+*CLOAD, LOAD CASE=2, AMPLITUDE=Amp1,SECTOR=3
+1,3,5.
+*END STEP
+*STEP
+*CLOAD
+2,1,-4.
+*END STEP
+*STEP
+*CLOAD,OP=NEW
+3,3,1.
+2,3,1.
+*END STEP
+*STEP
+*CLOAD,OP=NEW
+*END STEP
+*STEP
+*CLOAD,OP=NEW
+1,1,1.
+*CLOAD,OP=NEW
+2,1,1.
+*END STEP
+`)
+	ls := f.Steps[0].Cloads
+	if len(ls) != 3 || ls[0].Amplitude != "A1" || ls[0].TimeDelay != 1 ||
+		ls[1].Value != 0 || ls[2].LoadCase != 2 || ls[2].Sector != 3 {
+		t.Fatalf("not valid loads: %#v", ls)
+	}
+	if n := strings.Count(f.Steps[0].String(), "*CLOAD"); n != 2 {
+		t.Errorf("not valid amount of cload blocks: %d", n)
+	}
+	if n := strings.Count(f.Steps[4].String(), "*CLOAD, OP=NEW"); n != 2 {
+		t.Errorf("blocks with OP=NEW must not be merged: %d", n)
+	}
+	for i, expect := range []int{5, 5, 2, 0, 1} {
+		loads, err := f.Steps[i].EffectiveCloads(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loads) != expect {
+			t.Errorf("step %d: not valid amount of loads: %#v", i, loads)
+		}
+	}
+	loads, err := f.Steps[1].EffectiveCloads(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loads[2].Position != "2" || loads[2].Value != -4 {
+		t.Errorf("load is not replaced: %#v", loads[2])
+	}
+	var s inp.Step
+	if _, err := s.EffectiveCloads(f); err == nil {
+		t.Errorf("step not from model must be error")
+	}
+}
+
+func TestModelChange(t *testing.T) {
+	f := roundTrip(t, `
+*NODE
+1,0,0,0
+2,1,0,0
+3,2,0,0
+4,3,0,0
+*ELEMENT,TYPE=T3D2,ELSET=E1
+1,1,2
+2,2,3
+*ELEMENT,TYPE=T3D2,ELSET=E2
+3,3,4
+*STEP
+*MODEL CHANGE,TYPE=ELEMENT,REMOVE
+E2
+*STATIC
+*END STEP
+*STEP
+*MODEL CHANGE,TYPE=ELEMENT,REMOVE
+1
+*MODEL CHANGE,TYPE=ELEMENT,ADD=STRAIN FREE
+3
+*MODEL CHANGE,TYPE=CONTACT PAIR,REMOVE
+depf,indf
+*STATIC
+*END STEP
+`)
+	mcs := f.Steps[1].ModelChanges
+	if len(mcs) != 3 || !mcs[1].Add || mcs[1].AddMode != "STRAIN FREE" ||
+		mcs[2].Type != "CONTACT PAIR" || mcs[2].Pairs[0] != [2]string{"DEPF", "INDF"} {
+		t.Errorf("not valid model changes: %#v", mcs)
+	}
+	for i, expect := range [][]int{{1, 2}, {2, 3}} {
+		els, err := f.ActiveElements(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(els) != fmt.Sprint(expect) {
+			t.Errorf("step %d: not valid active elements: %v", i, els)
+		}
+	}
+}
+
+func TestCyclicSymmetry(t *testing.T) {
+	f := roundTrip(t, `
+*NODE,NSET=NALL
+1,1,0,0
+2,2,0,0
+*NSET,NSET=N1
+1,2
+*ELEMENT,TYPE=T3D2,ELSET=E1
+1,1,2
+*CYCLIC SYMMETRY MODEL,N=4,NGRAPH=4,TIE=T1,CHECK=NO
+0.,0.,0.,0.,0.,1.
+*STEP
+*FREQUENCY
+10
+*SELECT CYCLIC SYMMETRY MODES,NMIN=0,NMAX=0
+*SELECT CYCLIC SYMMETRY MODES,NMIN=1
+*END STEP
+`)
+	c := f.CyclicSymmetryModels[0]
+	if c.N != 4 || c.NGraph != 4 || c.Tie != "T1" || c.Check != "NO" || c.B != [3]float64{0, 0, 1} {
+		t.Errorf("not valid cyclic symmetry model: %#v", c)
+	}
+	ms := f.Steps[0].CyclicSymmetryModes
+	if len(ms) != 2 || ms[0].NMax != 0 || !ms[0].IsNMax || ms[1].NMin != 1 || ms[1].IsNMax {
+		t.Errorf("not valid modes: %#v", ms)
+	}
+	if out := ms[0].String(); !strings.Contains(out, "NMAX=0") {
+		t.Errorf("NMAX=0 must be written: %s", out)
+	}
+	if out := (inp.CyclicSymmetryModes{}).String(); strings.Contains(out, "NMAX") {
+		t.Errorf("not given NMAX must not be written: %s", out)
+	}
+	e, err := f.ExpandCyclic(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Nodes) != 8 || len(e.Elements) != 4 || len(e.Nsets[0].Indexes) != 8 {
+		t.Fatalf("not valid expanded model")
+	}
+	if n := e.Nodes[4]; n.Index != 5 || math.Abs(n.Coord[0]+1) > 1e-12 || math.Abs(n.Coord[1]) > 1e-12 {
+		t.Errorf("not valid rotated node: %#v", n)
+	}
+	if el := e.Elements[3]; el.Index != 4 || el.Nodes[0] != 7 || el.Nodes[1] != 8 {
+		t.Errorf("not valid copied element: %#v", el)
+	}
+	// changes of expanded model are not influent on model
+	e.Steps[0].CyclicSymmetryModes[0].NMin = 2
+	e.CyclicSymmetryModels[0].N = 8
+	e.Nsets[0].Indexes[0] = 10
+	if f.Steps[0].CyclicSymmetryModes[0].NMin != 0 || f.CyclicSymmetryModels[0].N != 4 ||
+		f.Nsets[0].List()[0] != 1 {
+		t.Errorf("model is changed by expanded model")
+	}
+}
+
+func TestFluid(t *testing.T) {
+	f := roundTrip(t, `
+*MATERIAL,NAME=GAS
+*SPECIFIC GAS CONSTANT
+287.
+*FLUID CONSTANTS
+1.0022E3,1.69532E-05,270
+ 0.1002353938D+4, 0.1949281697D-04, 0.32315D+03
+*FLUID SECTION,ELSET=Ewater,MATERIAL=WATER,TYPE=PIPE MANNING
+1.E-2,2.5E-2,0.015
+*FLUID SECTION,ELSET=E2,MATERIAL=WATER,TYPE=CHANNEL STRAIGHT,MANNING
+1,2,3,4,5,6,7,8
+9
+*STEP
+*BOUNDARYF
+Sin,S,2,3,0.
+91, S4, 2,, 1.000000
+*MASS FLOW,AMPLITUDE=A1
+Sside,M,0.
+*END STEP
+`)
+	m := f.Materials[0]
+	if m.SpecificGasConstant != 287 || len(m.FluidConstants) != 2 ||
+		m.FluidConstants[1].Temperature != 323.15 {
+		t.Errorf("not valid fluid material: %#v", m)
+	}
+	fs := f.FluidSections
+	if len(fs) != 2 || fs[0].Type != "PIPE MANNING" || !fs[1].Manning || len(fs[1].Constants) != 9 {
+		t.Fatalf("not valid fluid sections: %#v", fs)
+	}
+	named, err := fs[0].NamedConstants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if named["n"] != 0.015 || named["A"] != 1e-2 {
+		t.Errorf("not valid named constants: %v", named)
+	}
+	for _, tc := range []struct {
+		fs    inp.FluidSection
+		name  string
+		value float64
+	}{
+		{inp.FluidSection{Type: "RESTRICTOR BEND IDEL CIRC", Constants: []float64{600, 600, 27.6, 300, 45}}, "alpha", 45},
+		{inp.FluidSection{Type: "LABYRINTH STEPPED", Constants: []float64{12.2, 0.55, 0, 534}}, "d", 534},
+		{inp.FluidSection{Type: "MOEHRING CENTRIPETAL", Constants: []float64{150, 300}}, "Rmax", 300},
+		{inp.FluidSection{Type: "GASPIPE FANNO ISOTHERMAL", Constants: []float64{7e-4, 0.03, 0.05}}, "L", 0.05},
+		{inp.FluidSection{Type: "CHANNEL STRAIGHT", Constants: []float64{12, 0.0009, 29.8}}, "dl", 29.8},
+	} {
+		named, err := tc.fs.NamedConstants()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if named[tc.name] != tc.value {
+			t.Errorf("%s: not valid named constants: %v", tc.fs.Type, named)
+		}
+	}
+	named, err = inp.FluidSection{Type: "CHANNEL SLUICE GATE", Constants: []float64{12, 0.0009, 2.5, 0, 3}}.NamedConstants()
+	if err != nil || len(named) != 4 || named["w"] != 2.5 || named["label"] != 3 {
+		t.Errorf("not valid sluice gate constants: %v %v", named, err)
+	}
+	if _, err = (inp.FluidSection{Type: "NOT EXIST"}).NamedConstants(); err == nil {
+		t.Errorf("unknown type must be error")
+	}
+	s := f.Steps[0]
+	if len(s.BoundaryFs) != 2 || s.BoundaryFs[0].Finish != 3 || s.BoundaryFs[1].Face != "S4" ||
+		s.BoundaryFs[1].Factor != 1 {
+		t.Errorf("not valid boundaryf: %#v", s.BoundaryFs)
+	}
+	if len(s.MassFlows) != 1 || s.MassFlows[0].Amplitude != "A1" || s.MassFlows[0].Label != "M" {
+		t.Errorf("not valid mass flow: %#v", s.MassFlows)
+	}
+}
+
+func TestFluidBoundaryNew(t *testing.T) {
+	f := roundTrip(t, `
+*STEP
+*STATIC
+*BOUNDARYF
+SIN,S,2,3,0.
+*MASS FLOW
+SSIDE,M,1.
+*END STEP
+*STEP
+*STATIC
+*BOUNDARYF,OP=NEW
+*MASS FLOW,OP=NEW
+*END STEP
+*STEP
+*STATIC
+*BOUNDARYF,OP=NEW
+SIN,S,2,3,1.
+*BOUNDARYF,OP=NEW
+SOUT,S,1,,2.
+*END STEP
+`)
+	out := f.Steps[1].String()
+	for _, block := range []string{"*BOUNDARYF, OP=NEW\n", "*MASS FLOW, OP=NEW\n"} {
+		if !strings.Contains(out, block) {
+			t.Errorf("empty block with OP=NEW is lost: %s\n%s", block, out)
+		}
+	}
+	if n := strings.Count(f.Steps[2].String(), "*BOUNDARYF, OP=NEW\n"); n != 2 {
+		t.Errorf("blocks with OP=NEW must not be merged: %d\n%s", n, f.Steps[2])
+	}
+	if _, err := inp.Parse([]byte("*STEP\n*BOUNDARYF\n*END STEP\n")); err == nil {
+		t.Errorf("block without rows must have OP=NEW")
+	}
+}
+
+func TestOptimization(t *testing.T) {
+	f := roundTrip(t, `
+*NODE,NSET=NALL
+1,0,0,0
+*DESIGN VARIABLES,TYPE=COORDINATE
+NALL
+*STEP
+*SENSITIVITY
+*OBJECTIVE
+STRESS,NALL,10.,10.
+*CONSTRAINT
+MASS,Eall,LE,1.,
+DISPLACEMENT,N1,GE,,2.
+*FILTER,TYPE=LINEAR,EDGE PRESERVATION=YES,DIRECTION WEIGHTING=YES
+2.
+*END STEP
+*STEP
+*FEASIBLE DIRECTION
+*OBJECTIVE
+EIGENFREQUENCY
+*END STEP
+*STEP
+*SENSITIVITY
+*OBJECTIVE
+STRAIN ENERGY,EALL
+MASS,EALL
+*END STEP
+`)
+	if dv := f.DesignVariables; dv.Type != "COORDINATE" || dv.Nset != "NALL" {
+		t.Errorf("not valid design variables: %#v", dv)
+	}
+	s := f.Steps[0]
+	if s.Procedure() != "*SENSITIVITY" || f.Steps[1].Procedure() != "*FEASIBLE DIRECTION" {
+		t.Errorf("not valid procedures")
+	}
+	if objs := s.Sensitivity.Objectives; len(objs) != 1 || objs[0].Type != inp.ObjectiveStress ||
+		objs[0].Set != "NALL" || len(objs[0].Parameters) != 2 {
+		t.Errorf("not valid objective: %#v", objs)
+	}
+	if cs := s.Sensitivity.Constraints; len(cs) != 2 || cs[0].Relation != "LE" ||
+		cs[0].Relative != 1 || cs[1].Absolute != 2 {
+		t.Errorf("not valid constraints: %#v", cs)
+	}
+	if fl := s.Sensitivity.Filter; fl == nil || fl.Radius != 2 || fl.DirectionWeighting != "YES" {
+		t.Errorf("not valid filter: %#v", fl)
+	}
+	if objs := f.Steps[1].Sensitivity.Objectives; len(objs) != 1 || objs[0].Type != inp.ObjectiveEigenfrequency {
+		t.Errorf("not valid objective: %#v", objs)
+	}
+	objs := f.Steps[2].Sensitivity.Objectives
+	if len(objs) != 2 || objs[0].Type != inp.ObjectiveStrainEnergy || objs[1].Type != inp.ObjectiveMass {
+		t.Errorf("not valid objectives: %#v", objs)
+	}
+	if out := f.Steps[2].String(); strings.Count(out, "*OBJECTIVE") != 1 {
+		t.Errorf("objectives must be written in one block:\n%s", out)
+	}
+}
+
+func TestBeamSection(t *testing.T) {
+	f := roundTrip(t, `
+*BEAM SECTION,ELSET=E1,MATERIAL=EL,SECTION=RECT,OFFSET1=0.5
+0.05,0.10
+0.,0.,1.
+*BEAM SECTION,ELSET=E2,MATERIAL=EL,SECTION=PIPE
+.11,.01
+1.d0,0.d0,0.d0
+*BEAM SECTION,ELSET=E3,MATERIAL=EL,SECTION=BOX
+1.,1.,.1,.1,.1,.1
+0.,0.,1.
+*BEAM SECTION,ELSET=E4,MATERIAL=EL,SECTION=I
+0.,0.3,0.2,0.2,0.02,0.02,0.01
+0.,0.,1.
+*BEAM SECTION,ELSET=E5,MATERIAL=EL,SECTION=GENERAL
+0.0625,3.2552e-4,0.,3.2552e-4,0.8333
+0.,1.,0.
+*BEAM GENERAL SECTION,ELSET=E6,MATERIAL=EL
+0.0625,3.2552e-4,0.,3.2552e-4,5.5e-4
+0.,1.,0.
+*BEAM SECTION,ELSET=E7,MATERIAL=EL,SECTION=CIRC
+0.05,0.05
+`)
+	if len(f.BeamSections) != 7 || f.BeamSections[0].Offset1 != 0.5 || !f.BeamSections[5].General {
+		t.Fatalf("not valid beam sections: %#v", f.BeamSections)
+	}
+	eq := func(a, b float64) bool { return math.Abs(a-b) <= 1e-6*math.Abs(b) }
+	for i, expect := range []inp.SectionProperties{
+		{Area: 0.005, Iyy: 0.05 * 1e-3 / 12, Izz: 0.1 * 1.25e-4 / 12},
+		{Area: math.Pi * (0.11*0.11 - 0.1*0.1), Iyy: math.Pi * (math.Pow(0.11, 4) - 1e-4) / 4},
+		{Area: 0.36, Iyy: (1 - math.Pow(0.8, 4)) / 12, J: 4 * 0.81 * 0.81 / (4 * 0.9 / 0.1)},
+		{Area: 0.0106, Iyy: 1.71713333333e-4, J: (2*0.2*8e-6 + 0.26*1e-6) / 3},
+		{Area: 0.0625, Iyy: 3.2552e-4, J: 2 * 3.2552e-4},
+		{Area: 0.0625, Iyy: 3.2552e-4, J: 5.5e-4},
+		{Area: math.Pi * 0.0025, Iyy: math.Pi * math.Pow(0.05, 4) / 4},
+	} {
+		p, err := f.BeamSections[i].Properties()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !eq(p.Area, expect.Area) || !eq(p.Iyy, expect.Iyy) ||
+			(expect.Izz != 0 && !eq(p.Izz, expect.Izz)) || (expect.J != 0 && !eq(p.J, expect.J)) {
+			t.Errorf("section %d: not valid properties: %#v", i, p)
+		}
+	}
+	f = roundTrip(t, `
+*beam section, material=steel, elset=elall, section=rectangular
+10,10
+0.0, 1.0, 0.0
+*BEAM SECTION,ELSET=E1,MATERIAL=ALUM,SECTION=RECT,NODAL THICKNESS
+.25,.25
+1.d0,0.d0,0.d0
+`)
+	if bs := f.BeamSections; len(bs) != 2 || bs[0].Section != "RECT" || bs[0].NodalThickness ||
+		!bs[1].NodalThickness || bs[1].Thks != [2]float64{0.25, 0.25} {
+		t.Errorf("not valid beam sections: %#v", bs)
+	}
+	if out := f.BeamSections[1].String(); !strings.Contains(out, "NODAL THICKNESS") {
+		t.Errorf("nodal thickness is not written: %s", out)
+	}
+	b := inp.BeamSection{Section: "RECT", Elset: "E", Material: "M", Thks: [2]float64{1, 2}}
+	if p, err := b.Properties(); err != nil || p.Area != 2 || !strings.Contains(b.String(), "\n1.00000,2.00000\n") {
+		t.Errorf("deprecated thicknesses are not used: %v %#v\n%s", err, p, b)
+	}
+}
+
+func TestCompositeShell(t *testing.T) {
+	f := roundTrip(t, `
+*MATERIAL,NAME=STEEL
+*ELASTIC
+210000,0.3
+*SHELL SECTION,ELSET=E1,COMPOSITE
+0.1,,STEEL,OR1
+0.2,3,STEEL,OR2
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+0.1,,STEEL
+*SHELL SECTION,ELSET=E2,MATERIAL=STEEL
+0.3
+*MATERIAL,NAME=ALU
+*ELASTIC
+70000,0.3
+*SHELL SECTION,ELSET=E3,COMPOSITE
+0.1,,STEEL
+0.1,,ALU
+`)
+	ss := f.ShellSections[0]
+	if len(ss.Plies) != 13 || ss.Plies[0].Orientation != "OR1" || ss.Plies[1].IntegrationPoints != 3 {
+		t.Fatalf("not valid plies: %#v", ss.Plies)
+	}
+	if math.Abs(ss.TotalThickness()-1.4) > 1e-12 {
+		t.Errorf("not valid total thickness: %v", ss.TotalThickness())
+	}
+	ss = f.ShellSections[1]
+	a, b, d, err := f.ABD(ss)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q11 := 210000 / (1 - 0.09)
+	if math.Abs(a[0][0]-q11*0.3) > 1e-6 || math.Abs(b[0][0]) > 1e-6 ||
+		math.Abs(d[0][0]-q11*0.027/12) > 1e-6 || math.Abs(a[2][2]-210000/2.6*0.3) > 1e-6 {
+		t.Errorf("not valid ABD: %v %v %v", a, b, d)
+	}
+	ss.Offset = 0.5
+	if _, b, _, _ = f.ABD(ss); math.Abs(b[0][0]+q11*0.09/2) > 1e-6 {
+		t.Errorf("not valid coupling stiffness: %v", b)
+	}
+	if len(f.Materials) != 2 || f.Materials[1].Name != "ALU" {
+		t.Fatalf("not valid materials: %#v", f.Materials)
+	}
+	a, b, _, err = f.ABD(f.ShellSections[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	q11alu := 70000 / (1 - 0.09)
+	if math.Abs(a[0][0]-(q11+q11alu)*0.1) > 1e-6 || math.Abs(b[0][0]-(q11alu-q11)*0.01/2) > 1e-6 {
+		t.Errorf("not valid ABD of steel and aluminium plies: %v %v", a, b)
+	}
+	// orthotropic plies
+	f = roundTrip(t, `
+*MATERIAL,NAME=CFRP
+*ELASTIC,TYPE=ENGINEERING CONSTANTS
+135000.,10000.,10000.,0.3,0.3,0.4,5000.,5000.,
+3500.
+*SHELL SECTION,ELSET=E1,COMPOSITE
+0.1,,CFRP,OR1
+0.1,,CFRP,OR2
+`)
+	if m := f.Materials[0]; m.ElasticType != "ENGINEERING CONSTANTS" {
+		t.Errorf("not valid elastic type: %#v", m)
+	}
+	if _, _, _, err = f.ABD(f.ShellSections[0]); err == nil {
+		t.Errorf("orthotropic plies are not supported")
+	}
+}
+
+func TestNodalThickness(t *testing.T) {
+	f := roundTrip(t, `
+*NODE, NSET=NALL
+1, 0, 0, 0
+2, 1, 0, 0
+3, 1, 1, 0
+4, 0, 1, 0
+*ELEMENT, TYPE=S4, ELSET=E1
+1, 1, 2, 3, 4
+*NSET,NSET=LEFT
+1,4
+*MATERIAL,NAME=EL
+*ELASTIC
+210000,0.3
+*SHELL SECTION,ELSET=E1,MATERIAL=EL,NODAL THICKNESS
+0.05
+*NODAL THICKNESS
+LEFT,0.1
+2,0.2,0.5
+3,0.3
+*NORMAL
+1,1,-0.1,1.,0.
+1,4,0.,0.,1.
+`)
+	if len(f.NodalThicknesses) != 3 || f.NodalThicknesses[1].Thickness != [2]float64{0.2, 0.5} {
+		t.Fatalf("not valid nodal thicknesses: %#v", f.NodalThicknesses)
+	}
+	if len(f.Normals) != 2 || f.Normals[0].Node != 1 || f.Normals[0].Direction != [3]float64{-0.1, 1, 0} {
+		t.Fatalf("not valid normals: %#v", f.Normals)
+	}
+	thk, err := f.ElementThickness(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(thk-0.175) > 1e-12 {
+		t.Errorf("not valid element thickness: %v", thk)
+	}
+	thks, err := f.ElementThicknesses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thks) != 1 || thks[1] != thk {
+		t.Errorf("not valid element thicknesses: %v", thks)
+	}
+	nts := f.NodalThicknesses
+	f.NodalThicknesses = nts[:1]
+	if thks, err = f.ElementThicknesses(); err == nil || thks == nil || len(thks) != 0 {
+		t.Errorf("expect error without nodal thicknesses: %v %v", thks, err)
+	}
+	f.NodalThicknesses = nts
+	f.ShellSections[0].NodalThickness = false
+	if thk, _ = f.ElementThickness(1); thk != 0.05 {
+		t.Errorf("not valid section thickness: %v", thk)
+	}
+	if _, err = f.ElementThickness(2); err == nil {
+		t.Errorf("expect error for not exist element")
+	}
+}
+
+func TestTimePoints(t *testing.T) {
+	f := roundTrip(t, `
+*TIME POINTS,NAME=T1
+.4,.6,.9,1.,1.1,1.2,1.3,1.4,
+1.5,1.6
+*TIME POINTS,NAME=T2,GENERATE
+0.,1.,0.25
+2.,3.,0.5
+*TIME POINTS,NAME=T3
+5.E-5
+*STEP
+*STATIC
+*NODE PRINT,NSET=NALL,TIME POINTS=T1
+U
+*EL FILE,TIME POINTS=T2
+S
+*END STEP
+`)
+	if len(f.TimePoints) != 3 || len(f.TimePoints[0].Time) != 10 || len(f.TimePoints[2].Time) != 1 {
+		t.Fatalf("not valid time points: %#v", f.TimePoints)
+	}
+	times, err := f.TimePoints[1].Times()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(times) != "[0 0.25 0.5 0.75 1 2 2.5 3]" {
+		t.Errorf("not valid generated times: %v", times)
+	}
+	if err = f.CheckTimePoints(); err != nil {
+		t.Error(err)
+	}
+	f.Steps[0].ElFiles[0].TimePoints = "T4"
+	if err = f.CheckTimePoints(); err == nil {
+		t.Errorf("expect error for not defined time points")
+	}
+}
+
+func TestOutputs(t *testing.T) {
+	f := roundTrip(t, `
+*STEP
+*STATIC
+*NODE FILE,FREQUENCY=0,CONTACT ELEMENT
+U,RF
+*EL FILE,SECTION FORCES,OUTPUT=2D
+S,
+E
+*ELEMENT OUTPUT,ELSET=EALL
+PEEQ
+*NODE PRINT,NSET=NALL,FREQUENCYF=100,TOTALS=ONLY,GLOBAL=YES
+U
+*EL PRINT,ELSET=EALL
+ENER
+*CONTACT FILE
+CDISP,CSTRESS
+*CONTACT PRINT,SLAVE=SSLAV,MASTER=SMAST,FREQUENCY=10
+CELS
+*SECTION PRINT,SURFACE=S1,NAME=SP1
+SOF,SOM
+*END STEP
+`)
+	s := f.Steps[0]
+	if pr := s.NodeFiles[0]; pr.Frequency != 0 || !pr.IsFrequency || pr.IsFrequencyF || !pr.ContactElement ||
+		len(pr.Options) != 2 || pr.Options[1] != inp.OutputRF {
+		t.Errorf("not valid node file: %#v", pr)
+	}
+	if pr := s.ElFiles[0]; !pr.SectionForces || pr.Output != "2D" || len(pr.Options) != 2 {
+		t.Errorf("not valid element file: %#v", pr)
+	}
+	if pr := s.NodePrints[0]; pr.FrequencyF != 100 || pr.Total != "ONLY" || !pr.Global {
+		t.Errorf("not valid node print: %#v", pr)
+	}
+	if out := s.String(); !strings.Contains(out, "FREQUENCY=0") {
+		t.Errorf("FREQUENCY=0 must be written:\n%s", out)
+	}
+	st := inp.Step{NodeFiles: []inp.Print{{Options: []inp.OutputVariable{inp.OutputU}}}}
+	if out := st.String() + (inp.SectionPrint{Name: "SP", Surface: "S"}).String(); strings.Contains(out, "FREQUENCY") {
+		t.Errorf("not given frequency must not be written:\n%s", out)
+	}
+	if len(s.ElementOutputs) != 1 || len(s.ContactFiles) != 1 || s.ContactPrints[0].Slave != "SSLAV" {
+		t.Errorf("not valid contact output: %#v", s)
+	}
+	if sp := s.SectionPrints[0]; sp.Name != "SP1" || sp.Surface != "S1" || len(sp.Options) != 2 {
+		t.Errorf("not valid section print: %#v", sp)
+	}
+	if err := f.CheckOutputs(); err != nil {
+		t.Fatal(err)
+	}
+	for _, wrong := range []func(s *inp.Step){
+		func(s *inp.Step) { s.NodePrints[0].Options = []inp.OutputVariable{inp.OutputS} },
+		func(s *inp.Step) { s.ElPrints[0].Options = []inp.OutputVariable{inp.OutputHFL} },
+		func(s *inp.Step) { s.NodeFiles[0].Options = []inp.OutputVariable{inp.OutputPU} },
+		func(s *inp.Step) { s.SectionPrints[0].Options = []inp.OutputVariable{"XYZ"} },
+	} {
+		step := f.Steps[0]
+		step.NodePrints = append([]inp.Print{}, step.NodePrints...)
+		step.ElPrints = append([]inp.Print{}, step.ElPrints...)
+		step.NodeFiles = append([]inp.Print{}, step.NodeFiles...)
+		step.SectionPrints = append([]inp.SectionPrint{}, step.SectionPrints...)
+		wrong(&step)
+		g := f
+		g.Steps = []inp.Step{step}
+		if err := g.CheckOutputs(); err == nil {
+			t.Errorf("expect error for step: %#v", step)
+		}
+	}
+}
+
+func TestDiscreteElements(t *testing.T) {
+	f := roundTrip(t, `
+*NODE, NSET=NALL
+1, 0, 0, 0
+2, 1, 0, 0
+3, 1, 1, 0
+4, 0, 1, 0
+5, 0, 0, 1
+6, 1, 0, 1
+7, 1, 1, 1
+8, 0, 1, 1
+9, 3, 0, 0
+*ELEMENT, TYPE=C3D8, ELSET=EALL
+1, 1, 2, 3, 4, 5, 6, 7, 8
+*ELEMENT, TYPE=MASS, ELSET=EM
+2, 9
+*ELEMENT, TYPE=DASHPOTA, ELSET=EDASH
+3, 1, 9
+*MATERIAL,NAME=EL
+*DENSITY
+2.
+*SOLID SECTION,ELSET=EALL,MATERIAL=EL
+*MASS,ELSET=EM
+6.
+*ROTARY INERTIA,ELSET=EM
+1.,2.,3.
+*DASHPOT,ELSET=EDASH
+
+1.E-6,63000.
+1.E-7,63711.56
+`)
+	if len(f.Masses) != 1 || f.Masses[0].Value != 6 {
+		t.Fatalf("not valid masses: %#v", f.Masses)
+	}
+	if len(f.RotaryInertias) != 1 || f.RotaryInertias[0].Inertia != [6]float64{1, 2, 3} {
+		t.Fatalf("not valid rotary inertias: %#v", f.RotaryInertias)
+	}
+	if len(f.Dashpots) != 1 || len(f.Dashpots[0].Constants) != 2 ||
+		f.Dashpots[0].Constants[1].Frequency != 63711.56 {
+		t.Fatalf("not valid dashpots: %#v", f.Dashpots)
+	}
+	if err := f.CheckDiscreteSections(); err != nil {
+		t.Fatal(err)
+	}
+	mass, center, err := f.MassProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(mass-8) > 1e-12 || math.Abs(center[0]-2.375) > 1e-12 ||
+		math.Abs(center[1]-0.125) > 1e-12 || math.Abs(center[2]-0.125) > 1e-12 {
+		t.Errorf("not valid mass properties: %v %v", mass, center)
+	}
+	f.Elements = append(f.Elements, inp.Element{Index: 10, Type: "CPS4", Elset: "EP", Nodes: []int{1, 2, 3, 4}})
+	f.SolidSections = append(f.SolidSections, inp.SolidSection{Elset: "EP", Material: f.Materials[0].Name})
+	if _, _, err = f.MassProperties(); err == nil || !strings.Contains(err.Error(), "CPS4") {
+		t.Errorf("expect error for plane element: %v", err)
+	}
+	f.Masses[0].Elset = "EDASH"
+	if err := f.CheckDiscreteSections(); err == nil {
+		t.Errorf("expect error for not valid element type")
+	}
+}
+
+func TestCoupling(t *testing.T) {
+	f := roundTrip(t, `
+*NODE, NSET=NALL
+1, 0, 0, 0
+2, 1, 0, 0
+3, 1, 1, 0
+4, 0, 1, 0
+262, 0.5, 0.5, 1
+*ELEMENT, TYPE=S4, ELSET=EALL
+1, 1, 2, 3, 4
+*SURFACE,NAME=S1
+1,SPOS
+*COUPLING,REF NODE=262,SURFACE=S1,CONSTRAINT NAME=CN1
+*KINEMATIC
+1,3
+5
+*COUPLING,REF NODE=262,SURFACE=S1,ORIENTATION=OR1,CONSTRAINT NAME=CN2
+*DISTRIBUTING
+6,6
+*ELEMENT,TYPE=DCOUP3D,ELSET=E1
+33,262
+*DISTRIBUTING COUPLING,ELSET=E1
+1,0.5
+3,2.
+`)
+	if len(f.Couplings) != 2 {
+		t.Fatalf("not valid couplings: %#v", f.Couplings)
+	}
+	c := f.Couplings[0]
+	if c.RefNode != 262 || c.Surface != "S1" || c.ConstraintName != "CN1" ||
+		c.Type != inp.CouplingKinematic || fmt.Sprint(c.Dofs) != "[[1 3] [5 0]]" {
+		t.Errorf("not valid kinematic coupling: %#v", c)
+	}
+	c = f.Couplings[1]
+	if c.Orientation != "OR1" || c.Type != inp.CouplingDistributing || fmt.Sprint(c.Dofs) != "[[6 6]]" {
+		t.Errorf("not valid distributing coupling: %#v", c)
+	}
+	if ds := f.DistributingCouplings; len(ds) != 1 || ds[0].ElsetName != "E1" ||
+		fmt.Sprint(ds[0].Weights) != "[{1 0.5} {3 2}]" {
+		t.Errorf("not valid distributing couplings: %#v", ds)
+	}
+	if _, err := inp.Parse([]byte("*COUPLING,REF NODE=1,SURFACE=S1,CONSTRAINT NAME=CN\n*DISTRIBUTING,WEIGHTING METHOD=UNIFORM\n1,3\n")); err == nil {
+		t.Errorf("WEIGHTING METHOD is not parameter of CalculiX")
+	}
+
+	c, err := f.AddCoupling("CN3", [3]float64{0.5, 0.5, 2}, "S1", inp.CouplingKinematic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.RefNode != 263 || len(f.Couplings) != 3 || f.Nodes[len(f.Nodes)-1].Index != 263 {
+		t.Errorf("not valid added coupling: %#v", c)
+	}
+	if !strings.Contains(f.String(), "*COUPLING, REF NODE=263, SURFACE=S1, CONSTRAINT NAME=CN3\n*KINEMATIC\n1, 6\n") {
+		t.Errorf("not valid output:\n%s", f)
+	}
+	if _, err = f.AddCoupling("CN4", [3]float64{}, "S2", inp.CouplingKinematic); err == nil {
+		t.Errorf("expect error for not exist surface")
+	}
+	d, err := f.AddDistributingCoupling("E2", [3]float64{0, 0, 1}, []inp.CouplingWeight{{"1", 1}, {"2", 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if el := f.Elements[len(f.Elements)-1]; el.Index != 34 || el.Type != "DCOUP3D" || el.Elset != "E2" ||
+		fmt.Sprint(el.Nodes) != "[264]" || len(f.DistributingCouplings) != 2 || len(d.Weights) != 2 {
+		t.Errorf("not valid added distributing coupling: %#v %#v", el, d)
+	}
+	if !strings.Contains(f.String(), "*DISTRIBUTING COUPLING, ELSET=E2\n1, 1.00000\n2, 3.00000\n") {
+		t.Errorf("not valid output:\n%s", f)
+	}
+}
+
+func TestControls(t *testing.T) {
+	f := roundTrip(t, `
+*PHYSICAL CONSTANTS,ABSOLUTE ZERO=0.,STEFAN BOLTZMANN=5.669E-8
+*RESTART,READ,STEP=1
+*STEP
+*STATIC
+*RESTART,WRITE,FREQUENCY=2
+*CONTROLS,PARAMETERS=TIME INCREMENTATION
+100,100,9,100,10,4,,5
+.25,.5,.75,.85,,,1.5,,
+*CONTROLS,PARAMETERS=TIME INCREMENTATION
+,
+,,,,,,1.2
+*CONTROLS,PARAMETERS=FIELD
+0.25,0.25,0.01,,0.02,1.E-5,1.E-3,1.E-8
+*CONTROLS,PARAMETERS=CONTACT
+0.002,0.2,50,30
+*CONTROLS,PARAMETERS=LINE SEARCH
+,1.,1.,
+*CONTROLS,RESET
+*END STEP
+`)
+	if p := f.PhysicalConstants; !p.IsAbsoluteZero || p.AbsoluteZero != 0 || p.StefanBoltzmann != 5.669e-8 {
+		t.Errorf("not valid physical constants: %#v", p)
+	}
+	if r := f.Restart; !r.Read || r.Step != 1 {
+		t.Errorf("not valid restart: %#v", r)
+	}
+	s := f.Steps[0]
+	if r := s.Restart; !r.Write || r.Frequency != 2 {
+		t.Errorf("not valid step restart: %#v", r)
+	}
+	if len(s.Controls) != 6 {
+		t.Fatalf("not valid controls: %#v", s.Controls)
+	}
+	if c := s.Controls[0].TimeIncrementation; c.I0 != 100 || c.IC != 100 || c.IS != 0 || c.IA != 5 ||
+		c.Df != 0.25 || c.DA != 0.85 || c.DD != 1.5 {
+		t.Errorf("not valid time incrementation: %#v", c)
+	}
+	if c := s.Controls[1].TimeIncrementation; c.I0 != 0 || c.DD != 1.2 {
+		t.Errorf("not valid time incrementation: %#v", c)
+	}
+	if c := s.Controls[2].Field; c.Rn != 0.25 || c.Qu != 0 || c.Rl != 1e-8 {
+		t.Errorf("not valid field controls: %#v", c)
+	}
+	if c := s.Controls[3].Contact; c.Delcon != 0.002 || c.KScaleMax != 50 || c.Itf2f != 30 {
+		t.Errorf("not valid contact controls: %#v", c)
+	}
+	if c := s.Controls[4].LineSearch; c.SMax != 1 || c.SMin != 1 {
+		t.Errorf("not valid line search controls: %#v", c)
+	}
+	if !s.Controls[5].Reset {
+		t.Errorf("not valid reset controls: %#v", s.Controls[5])
+	}
+}
+
+func TestSubmodel(t *testing.T) {
+	global := roundTrip(t, `
+*NODE, NSET=NALL
+1, 0, 0, 0
+2, 2, 0, 0
+3, 2, 2, 0
+4, 0, 2, 0
+5, 0, 0, 2
+6, 2, 0, 2
+7, 2, 2, 2
+8, 0, 2, 2
+9, 4, 0, 0
+10, 4, 2, 0
+11, 4, 0, 2
+12, 4, 2, 2
+*ELEMENT, TYPE=C3D8, ELSET=E1
+1, 1, 2, 3, 4, 5, 6, 7, 8
+*ELEMENT, TYPE=C3D8, ELSET=E2
+2, 2, 9, 10, 3, 6, 11, 12, 7
+`)
+	local := roundTrip(t, `
+*NODE, NSET=NALL
+1, 0.5, 0.5, 0.5
+2, 3, 1, 1
+3, 2, 2, 2
+*NSET, NSET=N1
+1, 2
+*SUBMODEL,TYPE=NODE,INPUT=global.frd
+N1
+3
+*SUBMODEL,TYPE=NODE,INPUT=global.frd,GLOBAL ELSET=E2
+3
+*STEP
+*STATIC
+*BOUNDARY,SUBMODEL,STEP=1
+N1,1,3
+*DSLOAD,SUBMODEL,STEP=1
+S1,P
+*DSLOAD
+S1,P,0.01
+*END STEP
+`)
+	if len(local.Submodels) != 2 {
+		t.Fatalf("not valid submodels: %#v", local.Submodels)
+	}
+	s := local.Submodels[1]
+	if s.Type != "NODE" || s.Input != "global.frd" || s.GlobalElset != "E2" ||
+		fmt.Sprint(s.Sets) != "[3]" {
+		t.Errorf("not valid submodel: %#v", s)
+	}
+	loads := local.Steps[0].Dsloads
+	if len(loads) != 2 || !loads[0].Submodel || loads[0].Step != 1 ||
+		loads[1].Submodel || loads[1].Magnitude != 0.01 {
+		t.Errorf("not valid dsloads: %#v", loads)
+	}
+
+	if out := local.String(); !strings.Contains(out, "INPUT=global.frd") {
+		t.Errorf("not valid case of file name:\n%s", out)
+	}
+
+	var results inp.Dat
+	for _, n := range global.Nodes {
+		results.Displacements = append(results.Displacements, inp.Record{Node: n.Index})
+	}
+	elements, err := local.SubmodelGlobalElements(*global, &results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(elements) != "map[1:1 2:2 3:2]" {
+		t.Errorf("not valid global elements: %v", elements)
+	}
+	results.Displacements = results.Displacements[:len(results.Displacements)-1]
+	if _, err = local.SubmodelGlobalElements(*global, &results); err == nil {
+		t.Errorf("expect error for not found results")
+	}
+	if _, err = local.SubmodelGlobalElements(*global, nil); err != nil {
+		t.Error(err)
+	}
+
+	local.Nodes[1].Coord = [3]float64{5, 1, 1}
+	if _, err = local.SubmodelGlobalElements(*global, nil); err == nil {
+		t.Errorf("expect error for node outside of global mesh")
+	}
+}
+
+func TestDsloadNew(t *testing.T) {
+	f := roundTrip(t, `
+*STEP
+*STATIC
+*DSLOAD
+S1,P,1.
+*END STEP
+*STEP
+*STATIC
+*DSLOAD,OP=NEW
+*END STEP
+*STEP
+*STATIC
+*DSLOAD,OP=NEW
+S1,P,2.
+*DSLOAD,OP=NEW
+S2,P,3.
+*END STEP
+`)
+	if out := f.Steps[1].String(); !strings.Contains(out, "*DSLOAD, OP=NEW\n*END STEP") {
+		t.Errorf("empty block with OP=NEW is lost:\n%s", out)
+	}
+	if n := strings.Count(f.Steps[2].String(), "*DSLOAD, OP=NEW\n"); n != 2 {
+		t.Errorf("blocks with OP=NEW must not be merged: %d\n%s", n, f.Steps[2])
+	}
+	if _, err := inp.Parse([]byte("*STEP\n*DSLOAD\n*END STEP\n")); err == nil {
+		t.Errorf("block without rows must have OP=NEW")
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	f := roundTrip(t, `
+*NODE, NSET=NALL
+1, 0, 0, 0
+2, 1.5, 0, 0
+*ELEMENT, TYPE=T3D2, ELSET=EALL
+1, 1, 2
+*NSET, NSET=N1
+1, 2, 3, 4, 5, 6
+*TIME POINTS, NAME=T1
+0.25, 0.5
+`)
+	var buf bytes.Buffer
+	n, err := f.WriteTo(&buf, inp.WriteOptions{
+		Float:     inp.FloatShortest,
+		LowerCase: true,
+		PerLine:   4,
+		Comments:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if n != int64(len(out)) {
+		t.Errorf("not valid amount of bytes: %d != %d", n, len(out))
+	}
+	for _, s := range []string{
+		"** NODES\n*node,nset=nall\n",
+		"** ELEMENTS\n*element, type=t3d2, elset=eall\n",
+		"*nset, nset=n1\n",
+		"     4   , \n",
+		"*time points, name=t1\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("not found %q in output:\n%s", s, out)
+		}
+	}
+	f2, err := inp.Parse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.String() != f2.String() {
+		t.Errorf("not same:\n%s\n%s", f, f2)
+	}
+
+	buf.Reset()
+	if _, err = f.WriteTo(&buf, inp.WriteOptions{Float: inp.FloatFixed, Precision: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "    2, 1.500E+00, 0.000E+00, 0.000E+00\n") {
+		t.Errorf("not valid fixed format:\n%s", buf.String())
+	}
+	if _, err = f.WriteTo(&buf, inp.WriteOptions{PerLine: 17}); err == nil {
+		t.Errorf("expect error for more than 16 entries per line")
+	}
+}
+
+func TestElementContinuation(t *testing.T) {
+	f := roundTrip(t, `
+*ELEMENT, TYPE=C3D20, ELSET=Eall
+     1,    10,     2,    13,    95,   105,    34,   134,   222,    11,    12,
+          96,    93,   106,   133,   223,   220,   103,    33,   132,   219
+`)
+	if len(f.Elements) != 1 || len(f.Elements[0].Nodes) != 20 {
+		t.Errorf("not valid elements: %#v", f.Elements)
+	}
+}
+
+func TestSource(t *testing.T) {
+	content := `** model of beam
+*Material, Name=Steel
+*Elastic
+210000, 0.3
+** nodes
+*Node, Nset=Nall
+1, 0, 0, 0
+2, 1, 0, 0
+*Element, Type=T3D2, Elset=Eall
+1, 1, 2
+*Step
+*Static
+** loads
+*Cload
+2, 1, 10.
+*End Step
+** end of model
+`
+	f, err := inp.Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Source) != 5 {
+		t.Fatalf("not valid amount of keywords: %#v", f.Source)
+	}
+	if r := f.Source[1].Ranges; len(r) != 1 || r[0] != (inp.Range{Group: "NODE", From: 0, To: 2}) {
+		t.Errorf("not valid ranges of nodes: %#v", r)
+	}
+	var buf bytes.Buffer
+	if _, err = f.WriteTo(&buf, inp.WriteOptions{Source: true}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != content {
+		t.Errorf("not same:\n%s", buf.String())
+	}
+
+	f.Nodes[1].Coord[0] = 2
+	f.Nodes = append(f.Nodes, inp.Node{Index: 3, Nodeset: "NALL"})
+	buf.Reset()
+	if _, err = f.WriteTo(&buf, inp.WriteOptions{Source: true}); err != nil {
+		t.Fatal(err)
+	}
+	expect := `** model of beam
+*Material, Name=Steel
+*Elastic
+210000, 0.3
+** nodes
+*Node, Nset=Nall
+1, 0, 0, 0
+    2, +2.000000000000E+00, +0.000000000000E+00, +0.000000000000E+00
+    3, +0.000000000000E+00, +0.000000000000E+00, +0.000000000000E+00
+*Element, Type=T3D2, Elset=Eall
+1, 1, 2
+*Step
+*Static
+** loads
+*Cload
+2, 1, 10.
+*End Step
+** end of model
+`
+	if buf.String() != expect {
+		t.Errorf("not valid output:\n%s", buf.String())
+	}
+
+	// keywords inside of step
+	f.Steps[0].Cloads[0].Value = 20
+	f.Steps[0].Boundaries = append(f.Steps[0].Boundaries,
+		inp.Boundary{LoadLocation: "1", Start: 1, Finish: 3})
+	buf.Reset()
+	if _, err = f.WriteTo(&buf, inp.WriteOptions{Source: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), `*Step
+*Static
+** loads
+*Cload
+2, 1, 20.0000
+*BOUNDARY
+1, 1, 3
+*End Step
+** end of model
+`) {
+		t.Errorf("not valid output of step:\n%s", buf.String())
+	}
+}
+
+func TestSourceEdits(t *testing.T) {
+	write := func(f *inp.Model) string {
+		var buf bytes.Buffer
+		if _, err := f.WriteTo(&buf, inp.WriteOptions{Source: true}); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	t.Run("keyword without items", func(t *testing.T) {
+		f, err := inp.Parse([]byte("*STEP\n*CLOAD\n1,3,10.\n*DSLOAD,OP=NEW\n*END STEP\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Steps[0].Cloads[0].Value = 20
+		if out := write(f); out != "*STEP\n*CLOAD\n1, 3, 20.0000\n*DSLOAD,OP=NEW\n*END STEP\n" {
+			t.Errorf("not valid output:\n%s", out)
+		}
+	})
+	t.Run("sub-keyword of material", func(t *testing.T) {
+		f, err := inp.Parse([]byte("*MATERIAL,NAME=STEEL\n*ELASTIC\n210000,0.3\n*PLASTIC\n800.,0.\n*DENSITY\n7.8E-9\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(f.Source) != 1 {
+			t.Fatalf("sub-keywords must be part of material: %#v", f.Source)
+		}
+		f.Materials[0].Properties[0].E = 200000
+		out := write(f)
+		for _, keyword := range []string{"*ELASTIC", "*PLASTIC", "*DENSITY"} {
+			if n := strings.Count(out, keyword); n != 1 {
+				t.Errorf("not valid amount of %s: %d\n%s", keyword, n, out)
+			}
+		}
+		if !strings.Contains(out, "200000") {
+			t.Errorf("changed value is not written:\n%s", out)
+		}
+	})
+	t.Run("added row continues block", func(t *testing.T) {
+		f, err := inp.Parse([]byte("*STEP\n*CLOAD,OP=NEW\n1,3,10.\n*END STEP\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Steps[0].Cloads = append(f.Steps[0].Cloads, inp.Cload{Position: "2", Direction: 3,
+			Value: 5, CloadOptions: f.Steps[0].Cloads[0].CloadOptions})
+		out := write(f)
+		if out != "*STEP\n*CLOAD,OP=NEW\n1,3,10.\n2, 3, 5.00000\n*END STEP\n" {
+			t.Errorf("not valid output:\n%s", out)
+		}
+		g, err := inp.Parse([]byte(out))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loads, err := g.Steps[0].EffectiveCloads(g); err != nil || len(loads) != 2 {
+			t.Errorf("OP=NEW must not remove added load: %v %v", loads, err)
+		}
+	})
+}
+
+func TestThermalMaterials(t *testing.T) {
+	f := roundTrip(t, `
+*MATERIAL,NAME=EL
+*ELASTIC
+210000.,.3
+*CONDUCTIVITY
+50.,0.
+50.,100.
+*SPECIFIC HEAT
+446.E6
+*MATERIAL,NAME=MAT2
+*CONDUCTIVITY,TYPE=ORTHO
+1.E-4,4.E-4,1.E-4
+`)
+	if len(f.Materials) != 2 {
+		t.Fatalf("not valid materials: %#v", f.Materials)
+	}
+	m := f.Materials[0]
+	if len(m.Conductivities) != 2 || m.Conductivities[1].Values[0] != 50 ||
+		m.Conductivities[1].Temperature != 100 || len(m.SpecificHeats) != 1 ||
+		m.SpecificHeats[0].Value != 446e6 {
+		t.Errorf("not valid thermal properties: %#v", m)
+	}
+	m = f.Materials[1]
+	if m.ConductivityType != inp.ConductivityOrtho || len(m.Conductivities) != 1 ||
+		fmt.Sprint(m.Conductivities[0].Values) != "[0.0001 0.0004 0.0001]" {
+		t.Errorf("not valid orthotropic conductivity: %#v", m)
+	}
+	c := inp.Condition{Type: "TEMPERATURE", Lines: [][]string{{"NALL", "20."}}}
+	if out := c.String(); out != "*INITIAL CONDITIONS, TYPE=TEMPERATURE\nNALL, 20.\n" {
+		t.Errorf("not valid initial conditions: %q", out)
+	}
+	g := roundTrip(t, `
+*INITIAL CONDITIONS,TYPE=TEMPERATURE
+NALL,20.
+*INITIAL CONDITIONS,TYPE=FLUID VELOCITY
+NALL,1,1.
+`)
+	if ic := g.InitialConditions; len(g.Conditions) != 2 || ic.NodeSet != "NALL" || ic.TemperatureNode != 20 {
+		t.Errorf("not valid deprecated initial conditions: %#v", ic)
+	}
+	g.InitialConditions = inp.Condition{Type: "STRESS", NodeSet: "NALL"}
+	if n := strings.Count(g.String(), "*INITIAL CONDITIONS"); n != 2 {
+		t.Errorf("deprecated initial conditions must not be written: %d", n)
+	}
+	for _, file := range []string{"beamth.inp"} {
+		b, err := os.ReadFile(filepath.Join(data, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = inp.Parse(b); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+	}
+}
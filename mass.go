@@ -0,0 +1,456 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Mass
+//
+// Example:
+//
+//	*MASS,ELSET=EM
+//	0.9
+//
+// First line:
+//
+//	*MASS
+//	Enter the parameter ELSET and its value.
+//
+// Second line:
+//
+//	Mass.
+type Mass struct {
+	Elset string
+	Value float64
+}
+
+func (m Mass) String() string {
+	return fmt.Sprintf("*MASS, ELSET=%s\n%s\n", m.Elset, efmt.Sprint(m.Value))
+}
+
+func (f *Model) parseMass(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*MASS") {
+		return false, nil
+	}
+	var m Mass
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ELSET":
+			m.Elset = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid mass parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("mass must have one data line")
+		return
+	}
+	m.Value, err = parseFloat(fields(block[1])[0])
+	if err != nil {
+		return
+	}
+	f.Masses = append(f.Masses, m)
+	return true, nil
+}
+
+// Dashpot
+//
+// Example:
+//
+//	*DASHPOT,ELSET=EDASH
+//
+//	1.e-6,63000.
+//	1.e-7,63711.56
+//
+// First line:
+//
+//	*DASHPOT
+//	Enter the parameter ELSET and its value.
+//
+// Second line: enter a blank line.
+//
+// Following line:
+//
+//	Dashpot constant.
+//	Frequency (only for steady state dynamics calculations).
+//	Temperature.
+type Dashpot struct {
+	Elset     string
+	Constants []DashpotConstant
+}
+
+// DashpotConstant is line of dashpot constants
+type DashpotConstant struct {
+	Constant    float64
+	Frequency   float64
+	Temperature float64
+}
+
+func (d Dashpot) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*DASHPOT, ELSET=%s\n\n", d.Elset)
+	for _, c := range d.Constants {
+		fmt.Fprintf(&buf, "%s, %s, %s\n", efmt.Sprint(c.Constant),
+			efmt.Sprint(c.Frequency), efmt.Sprint(c.Temperature))
+	}
+	return buf.String()
+}
+
+func (f *Model) parseDashpot(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*DASHPOT") {
+		return false, nil
+	}
+	var d Dashpot
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ELSET":
+			d.Elset = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid dashpot parameter: %s", part)
+			return
+		}
+	}
+	// blank second line is removed by splitting into blocks
+	for _, line := range block[1:] {
+		var vs []float64
+		vs, err = parseFloats(line)
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0, 0)
+		d.Constants = append(d.Constants, DashpotConstant{
+			Constant:    vs[0],
+			Frequency:   vs[1],
+			Temperature: vs[2],
+		})
+	}
+	if len(d.Constants) == 0 {
+		err = fmt.Errorf("dashpot must have dashpot constant")
+		return
+	}
+	f.Dashpots = append(f.Dashpots, d)
+	return true, nil
+}
+
+// RotaryInertia
+//
+// Example:
+//
+//	*ROTARY INERTIA,ELSET=EM
+//	1.,1.,1.,0.,0.,0.
+//
+// First line:
+//
+//	*ROTARY INERTIA
+//	Enter the parameter ELSET and its value and, if necessary,
+//	the parameter ORIENTATION.
+//
+// Second line:
+//
+//	Ixx, Iyy, Izz, Ixy, Ixz, Iyz.
+type RotaryInertia struct {
+	Elset       string
+	Orientation string
+	Inertia     [6]float64
+}
+
+func (r RotaryInertia) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*ROTARY INERTIA, ELSET=%s", r.Elset)
+	if r.Orientation != "" {
+		fmt.Fprintf(&buf, ", ORIENTATION=%s", r.Orientation)
+	}
+	fmt.Fprintf(&buf, "\n")
+	for i, v := range r.Inertia {
+		if 0 < i {
+			fmt.Fprintf(&buf, ", ")
+		}
+		fmt.Fprintf(&buf, "%s", efmt.Sprint(v))
+	}
+	fmt.Fprintf(&buf, "\n")
+	return buf.String()
+}
+
+func (f *Model) parseRotaryInertia(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*ROTARY INERTIA") {
+		return false, nil
+	}
+	var r RotaryInertia
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ELSET":
+			r.Elset = value
+		case "ORIENTATION":
+			r.Orientation = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid rotary inertia parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("rotary inertia must have one data line")
+		return
+	}
+	vs, err := parseFloats(block[1])
+	if err != nil {
+		return
+	}
+	copy(r.Inertia[:], vs)
+	f.RotaryInertias = append(f.RotaryInertias, r)
+	return true, nil
+}
+
+// CheckDiscreteSections return error if element set of *MASS or
+// *ROTARY INERTIA has not MASS element or element set of *DASHPOT has
+// not DASHPOTA element.
+func (f Model) CheckDiscreteSections() error {
+	elements := f.elementByIndex()
+	check := func(keyword, elset, elType string) error {
+		indexes, err := f.ElsetIndexes(elset)
+		if err != nil {
+			return fmt.Errorf("%s: %v", keyword, err)
+		}
+		for _, index := range indexes {
+			el, ok := elements[index]
+			if !ok {
+				return fmt.Errorf("%s: not found element %d", keyword, index)
+			}
+			if el.Type != elType {
+				return fmt.Errorf("%s: element %d of set %s has type %s, but expect %s",
+					keyword, index, elset, el.Type, elType)
+			}
+		}
+		return nil
+	}
+	for _, m := range f.Masses {
+		if err := check("*MASS", m.Elset, "MASS"); err != nil {
+			return err
+		}
+	}
+	for _, r := range f.RotaryInertias {
+		if err := check("*ROTARY INERTIA", r.Elset, "MASS"); err != nil {
+			return err
+		}
+	}
+	for _, d := range f.Dashpots {
+		if err := check("*DASHPOT", d.Elset, "DASHPOTA"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tetraVolume return signed volume of tetrahedron
+func tetraVolume(a, b, c, d [3]float64) float64 {
+	return dot(sub(b, a), cross(sub(c, a), sub(d, a))) / 6
+}
+
+// tetras of solid elements by corner nodes
+var (
+	tetrasHexa  = [][4]int{{0, 1, 2, 6}, {0, 2, 3, 6}, {0, 3, 7, 6}, {0, 7, 4, 6}, {0, 4, 5, 6}, {0, 5, 1, 6}}
+	tetrasWedge = [][4]int{{0, 1, 2, 3}, {1, 2, 3, 4}, {2, 3, 4, 5}}
+	tetrasTetra = [][4]int{{0, 1, 2, 3}}
+)
+
+// elementMeasure return volume of solid element, area of shell or
+// plane element and length of beam or truss element with centroid.
+// Geometry of element is defined by corner nodes only.
+func elementMeasure(el Element, coords map[int][3]float64) (measure float64, center [3]float64, err error) {
+	corners := cornerNodes(el.Type)
+	if len(el.Nodes) < corners {
+		err = fmt.Errorf("element %d has not enough nodes", el.Index)
+		return
+	}
+	ps := make([][3]float64, corners)
+	for i := range ps {
+		var ok bool
+		if ps[i], ok = coords[el.Nodes[i]]; !ok {
+			err = fmt.Errorf("element %d: not found node %d", el.Index, el.Nodes[i])
+			return
+		}
+	}
+	add := func(m float64, c [3]float64) {
+		measure += m
+		for i := range center {
+			center[i] += m * c[i]
+		}
+	}
+	var tetras [][4]int
+	if strings.HasPrefix(el.Type, "C3D") {
+		switch corners {
+		case 8:
+			tetras = tetrasHexa
+		case 6:
+			tetras = tetrasWedge
+		case 4:
+			tetras = tetrasTetra
+		}
+	}
+	switch {
+	case tetras != nil:
+		for _, t := range tetras {
+			a, b, c, d := ps[t[0]], ps[t[1]], ps[t[2]], ps[t[3]]
+			var centroid [3]float64
+			for i := range centroid {
+				centroid[i] = (a[i] + b[i] + c[i] + d[i]) / 4
+			}
+			add(math.Abs(tetraVolume(a, b, c, d)), centroid)
+		}
+	case corners == 2:
+		var centroid [3]float64
+		for i := range centroid {
+			centroid[i] = (ps[0][i] + ps[1][i]) / 2
+		}
+		add(distance(ps[0], ps[1]), centroid)
+	default:
+		for k := 1; k+1 < corners; k++ {
+			a, b, c := ps[0], ps[k], ps[k+1]
+			var centroid [3]float64
+			for i := range centroid {
+				centroid[i] = (a[i] + b[i] + c[i]) / 3
+			}
+			add(norm(cross(sub(b, a), sub(c, a)))/2, centroid)
+		}
+	}
+	if measure != 0 {
+		for i := range center {
+			center[i] /= measure
+		}
+	}
+	return
+}
+
+// MassProperties return total mass and center of gravity of model.
+// Mass of solid, shell and beam elements is calculated by density of
+// section material, thickness of shell elements is calculated by
+// Model.ElementThickness. Mass elements of *MASS are included.
+// Elements of *SOLID SECTION must be three-dimensional elements C3D,
+// otherwise error is returned, because thickness of plane elements is
+// not taken into account.
+func (f Model) MassProperties() (mass float64, center [3]float64, err error) {
+	coords := f.nodeCoordinates()
+	elements := f.elementByIndex()
+	density := map[string]float64{}
+	for _, m := range f.Materials {
+		density[m.Name] = m.Density
+	}
+	add := func(m float64, c [3]float64) {
+		mass += m
+		for i := range center {
+			center[i] += m * c[i]
+		}
+	}
+	// each calls function for all elements of element set
+	each := func(elset string, fn func(el Element) error) error {
+		indexes, err := f.ElsetIndexes(elset)
+		if err != nil {
+			return err
+		}
+		for _, index := range indexes {
+			el, ok := elements[index]
+			if !ok {
+				return fmt.Errorf("not found element %d", index)
+			}
+			if err := fn(el); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, s := range f.SolidSections {
+		err = each(s.Elset, func(el Element) error {
+			if !strings.HasPrefix(el.Type, "C3D") {
+				return fmt.Errorf("mass of element %d with type %s is not supported",
+					el.Index, el.Type)
+			}
+			volume, c, err := elementMeasure(el, coords)
+			add(volume*density[s.Material], c)
+			return err
+		})
+		if err != nil {
+			return
+		}
+	}
+	var st shellThicknesses
+	if 0 < len(f.ShellSections) {
+		if st, err = f.shellThicknesses(); err != nil {
+			return
+		}
+	}
+	for _, s := range f.ShellSections {
+		err = each(s.Elset, func(el Element) error {
+			area, c, err := elementMeasure(el, coords)
+			if err != nil {
+				return err
+			}
+			var perArea float64
+			if s.NodalThickness && 0 < len(s.Plies) {
+				t, err := st.thickness(el.Index)
+				if err != nil {
+					return err
+				}
+				perArea = t * density[s.Plies[0].Material]
+			} else {
+				for _, p := range s.Plies {
+					perArea += p.Thickness * density[p.Material]
+				}
+			}
+			add(area*perArea, c)
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+	for _, s := range f.BeamSections {
+		var p SectionProperties
+		if p, err = s.Properties(); err != nil {
+			return
+		}
+		err = each(s.Elset, func(el Element) error {
+			length, c, err := elementMeasure(el, coords)
+			add(length*p.Area*density[s.Material], c)
+			return err
+		})
+		if err != nil {
+			return
+		}
+	}
+	for _, m := range f.Masses {
+		err = each(m.Elset, func(el Element) error {
+			if len(el.Nodes) == 0 {
+				return fmt.Errorf("mass element %d has not node", el.Index)
+			}
+			c, ok := coords[el.Nodes[0]]
+			if !ok {
+				return fmt.Errorf("mass element %d: not found node %d", el.Index, el.Nodes[0])
+			}
+			add(m.Value, c)
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+	if mass != 0 {
+		for i := range center {
+			center[i] /= mass
+		}
+	}
+	return
+}
@@ -0,0 +1,167 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ModelChange
+//
+// Examples:
+//
+//	*MODEL CHANGE,TYPE=ELEMENT,REMOVE
+//	37,38,E1
+//
+//	*MODEL CHANGE,TYPE=ELEMENT,ADD=STRAIN FREE
+//	E1
+//
+//	*MODEL CHANGE,TYPE=CONTACT PAIR,REMOVE
+//	depf,indf
+//
+// First line:
+//
+//	*MODEL CHANGE
+//	Enter the parameter TYPE (ELEMENT or CONTACT PAIR) and one of
+//	the parameters ADD or REMOVE. Parameter ADD may have value
+//	STRAIN FREE (default) or WITH STRAIN. Parameter
+//	MECHSTRAINTORESIDUAL is acceptable for TYPE=ELEMENT.
+//
+// Following line for TYPE=ELEMENT:
+//
+//	Element numbers or element set labels (maximum 16 entries per line).
+//
+// Following line for TYPE=CONTACT PAIR:
+//
+//	Slave surface.
+//	Master surface.
+type ModelChange struct {
+	Type                 string // ELEMENT or CONTACT PAIR
+	Add                  bool
+	AddMode              string // STRAIN FREE or WITH STRAIN
+	Remove               bool
+	MechStrainToResidual bool
+	List                 []string    // element numbers or element sets
+	Pairs                [][2]string // slave and master surfaces of contact pairs
+}
+
+func (mc ModelChange) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*MODEL CHANGE, TYPE=%s", mc.Type)
+	if mc.Add {
+		fmt.Fprintf(&buf, ", ADD")
+		if mc.AddMode != "" {
+			fmt.Fprintf(&buf, "=%s", mc.AddMode)
+		}
+	}
+	if mc.Remove {
+		fmt.Fprintf(&buf, ", REMOVE")
+	}
+	if mc.MechStrainToResidual {
+		fmt.Fprintf(&buf, ", MECHSTRAINTORESIDUAL")
+	}
+	fmt.Fprintf(&buf, "\n")
+	for i, entry := range mc.List {
+		fmt.Fprintf(&buf, "%s", entry)
+		if i == len(mc.List)-1 || (i+1)%8 == 0 {
+			fmt.Fprintf(&buf, "\n")
+		} else {
+			fmt.Fprintf(&buf, ", ")
+		}
+	}
+	for _, pair := range mc.Pairs {
+		fmt.Fprintf(&buf, "%s, %s\n", pair[0], pair[1])
+	}
+	return buf.String()
+}
+
+func (s *Step) parseModelChange(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*MODEL CHANGE") {
+		return false, nil
+	}
+	var mc ModelChange
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "TYPE":
+			mc.Type = strings.ToUpper(value)
+		case "ADD":
+			mc.Add = true
+			mc.AddMode = strings.ToUpper(value)
+		case "REMOVE":
+			mc.Remove = true
+		case "MECHSTRAINTORESIDUAL":
+			mc.MechStrainToResidual = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid model change parameter: %s", part)
+			return
+		}
+	}
+	if mc.Add == mc.Remove && !mc.MechStrainToResidual {
+		err = fmt.Errorf("model change must have parameter ADD or REMOVE: %s", block[0])
+		return
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		switch mc.Type {
+		case "ELEMENT":
+			for _, f := range fs {
+				if f != "" {
+					mc.List = append(mc.List, f)
+				}
+			}
+		case "CONTACT PAIR":
+			if len(fs) < 2 {
+				err = fmt.Errorf("not valid model change line: %s", line)
+				return
+			}
+			mc.Pairs = append(mc.Pairs, [2]string{fs[0], fs[1]})
+		default:
+			err = fmt.Errorf("not valid model change type: %s", mc.Type)
+			return
+		}
+	}
+	s.ModelChanges = append(s.ModelChanges, mc)
+	return true, nil
+}
+
+// ActiveElements return sorted indexes of elements alive in step with
+// index stepIndex. All elements are alive before the first step, and
+// element model changes of steps 0...stepIndex are applied in order.
+func (f Model) ActiveElements(stepIndex int) (indexes []int, err error) {
+	if stepIndex < 0 || len(f.Steps) <= stepIndex {
+		err = fmt.Errorf("not valid step index %d", stepIndex)
+		return
+	}
+	alive := map[int]bool{}
+	for _, el := range f.Elements {
+		alive[el.Index] = true
+	}
+	for _, s := range f.Steps[:stepIndex+1] {
+		for _, mc := range s.ModelChanges {
+			if mc.Type != "ELEMENT" || mc.Add == mc.Remove {
+				continue
+			}
+			for _, entry := range mc.List {
+				var els []int
+				els, err = f.elementLocation(entry)
+				if err != nil {
+					return
+				}
+				for _, el := range els {
+					alive[el] = mc.Add
+				}
+			}
+		}
+	}
+	for index, ok := range alive {
+		if ok {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	return
+}
@@ -0,0 +1,450 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// DesignVariables
+//
+// Examples:
+//
+//	*DESIGNVARIABLES,TYPE=COORDINATE
+//	Nall
+//
+//	*DESIGN VARIABLES,TYPE=ORIENTATION
+//
+// First line:
+//
+//	*DESIGNVARIABLES or *DESIGN VARIABLES
+//	Enter the parameter TYPE and its value (COORDINATE or ORIENTATION).
+//
+// Second line (only for TYPE=COORDINATE):
+//
+//	Node set with design variables.
+//	Name of local coordinate system (optional).
+type DesignVariables struct {
+	Type             string
+	Nset             string
+	CoordinateSystem string
+}
+
+func (dv DesignVariables) String() string {
+	if dv.Type == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*DESIGNVARIABLES, TYPE=%s\n", dv.Type)
+	if dv.Nset != "" {
+		fmt.Fprintf(&buf, "%s", dv.Nset)
+		if dv.CoordinateSystem != "" {
+			fmt.Fprintf(&buf, ", %s", dv.CoordinateSystem)
+		}
+		fmt.Fprintf(&buf, "\n")
+	}
+	return buf.String()
+}
+
+func (f *Model) parseDesignVariables(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*DESIGNVARIABLES") && !isHeader(block[0], "*DESIGN VARIABLES") {
+		return false, nil
+	}
+	var dv DesignVariables
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "TYPE":
+			dv.Type = strings.ToUpper(value)
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid design variables parameter: %s", part)
+			return
+		}
+	}
+	if dv.Type == "" {
+		err = fmt.Errorf("design variables must have parameter TYPE")
+		return
+	}
+	if 2 < len(block) {
+		err = fmt.Errorf("design variables must have maximal one data line")
+		return
+	}
+	if len(block) == 2 {
+		fs := append(fields(block[1]), "")
+		dv.Nset, dv.CoordinateSystem = fs[0], fs[1]
+	}
+	f.DesignVariables = dv
+	return true, nil
+}
+
+// Objective types
+const (
+	ObjectiveAllDisp        = "ALL-DISP"
+	ObjectiveDisplacement   = "DISPLACEMENT"
+	ObjectiveEigenfrequency = "EIGENFREQUENCY"
+	ObjectiveGreen          = "GREEN"
+	ObjectiveMass           = "MASS"
+	ObjectiveShapeEnergy    = "SHAPE ENERGY"
+	ObjectiveStrainEnergy   = "STRAIN ENERGY"
+	ObjectiveStress         = "STRESS"
+)
+
+// Objective
+//
+// Examples:
+//
+//	*OBJECTIVE
+//	EIGENFREQUENCY
+//
+//	*OBJECTIVE
+//	STRESS,NALL,10.,10.
+//
+//	*OBJECTIVE
+//	STRAIN ENERGY,EALL
+//	MASS,EALL
+//
+// First line:
+//
+//	*OBJECTIVE
+//
+// Following line (one line per objective):
+//
+//	Objective type.
+//	Node set or element set (optional).
+//	Only for STRESS: parameter rho of Kreisselmeier-Steinhauser
+//	function and target stress factor.
+type Objective struct {
+	Type       string
+	Set        string
+	Parameters []float64
+}
+
+func (o Objective) String() string {
+	if o.Type == "" {
+		return ""
+	}
+	return "*OBJECTIVE\n" + o.line()
+}
+
+func (o Objective) line() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s", o.Type)
+	if o.Set != "" || 0 < len(o.Parameters) {
+		fmt.Fprintf(&buf, ", %s", o.Set)
+	}
+	for _, p := range o.Parameters {
+		fmt.Fprintf(&buf, ", %s", efmt.Sprint(p))
+	}
+	fmt.Fprintf(&buf, "\n")
+	return buf.String()
+}
+
+func (s *Step) parseObjective(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*OBJECTIVE") {
+		return false, nil
+	}
+	if len(block) == 1 {
+		err = fmt.Errorf("objective must have data lines")
+		return
+	}
+	for _, line := range block[1:] {
+		fs := append(fields(line), "")
+		o := Objective{Type: strings.ToUpper(fs[0]), Set: fs[1]}
+		for _, field := range fs[2:] {
+			if field == "" {
+				continue
+			}
+			var v float64
+			v, err = parseFloat(field)
+			if err != nil {
+				return
+			}
+			o.Parameters = append(o.Parameters, v)
+		}
+		s.Sensitivity.Objectives = append(s.Sensitivity.Objectives, o)
+	}
+	return true, nil
+}
+
+// Constraint is line of *CONSTRAINT
+//
+// Example:
+//
+//	*CONSTRAINT
+//	MASS,Eall,LE,1.,
+//
+// Following line:
+//
+//	Constraint type (same as objective types).
+//	Node set or element set.
+//	LE (less or equal) or GE (greater or equal).
+//	Relative value of the constraint.
+//	Absolute value of the constraint.
+type Constraint struct {
+	Type     string
+	Set      string
+	Relation string
+	Relative float64
+	Absolute float64
+}
+
+func (s *Step) parseConstraint(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*CONSTRAINT") {
+		return false, nil
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 3 {
+			err = fmt.Errorf("not valid constraint line: %s", line)
+			return
+		}
+		c := Constraint{
+			Type:     strings.ToUpper(fs[0]),
+			Set:      fs[1],
+			Relation: strings.ToUpper(fs[2]),
+		}
+		var vs []float64
+		vs, err = parseFloats(strings.Join(fs[3:], ","))
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0)
+		c.Relative, c.Absolute = vs[0], vs[1]
+		s.Sensitivity.Constraints = append(s.Sensitivity.Constraints, c)
+	}
+	return true, nil
+}
+
+// Filter
+//
+// Examples:
+//
+//	*FILTER,TYPE=LINEAR,EDGE PRESERVATION=YES
+//	2.
+//
+//	*FILTER,EDGE PRESERVATION=YES
+//
+// First line:
+//
+//	*FILTER
+//	Enter any needed parameters and their values:
+//	TYPE (LINEAR, QUADRATIC or CUBIC), BOUNDARY WEIGHTING (YES or NO),
+//	EDGE PRESERVATION (YES or NO), DIRECTION WEIGHTING (YES or NO).
+//
+// Second line:
+//
+//	Filter radius.
+type Filter struct {
+	Type               string
+	BoundaryWeighting  string
+	EdgePreservation   string
+	DirectionWeighting string
+	Radius             float64
+}
+
+func (fl Filter) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*FILTER")
+	if fl.Type != "" {
+		fmt.Fprintf(&buf, ", TYPE=%s", fl.Type)
+	}
+	if fl.BoundaryWeighting != "" {
+		fmt.Fprintf(&buf, ", BOUNDARY WEIGHTING=%s", fl.BoundaryWeighting)
+	}
+	if fl.EdgePreservation != "" {
+		fmt.Fprintf(&buf, ", EDGE PRESERVATION=%s", fl.EdgePreservation)
+	}
+	if fl.DirectionWeighting != "" {
+		fmt.Fprintf(&buf, ", DIRECTION WEIGHTING=%s", fl.DirectionWeighting)
+	}
+	fmt.Fprintf(&buf, "\n")
+	if fl.Radius != 0 {
+		fmt.Fprintf(&buf, "%s\n", efmt.Sprint(fl.Radius))
+	}
+	return buf.String()
+}
+
+func (s *Step) parseFilter(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*FILTER") {
+		return false, nil
+	}
+	var fl Filter
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "TYPE":
+			fl.Type = value
+		case "BOUNDARY WEIGHTING":
+			fl.BoundaryWeighting = value
+		case "EDGE PRESERVATION":
+			fl.EdgePreservation = value
+		case "DIRECTION WEIGHTING":
+			fl.DirectionWeighting = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid filter parameter: %s", part)
+			return
+		}
+	}
+	switch len(block) {
+	case 1:
+	case 2:
+		fl.Radius, err = parseFloat(fields(block[1])[0])
+		if err != nil {
+			return
+		}
+	default:
+		err = fmt.Errorf("filter must have maximal one data line")
+		return
+	}
+	s.Sensitivity.Filter = &fl
+	return true, nil
+}
+
+// Sensitivity is step procedure of keyword *SENSITIVITY with
+// objective, constraints and filter of the step. Objective, constraints
+// and filter are also used by procedure *FEASIBLE DIRECTION.
+//
+// Example:
+//
+//	*SENSITIVITY
+//	*OBJECTIVE
+//	EIGENFREQUENCY
+//	*FILTER,TYPE=LINEAR,EDGE PRESERVATION=YES
+//	2.
+//
+// First and only line:
+//
+//	*SENSITIVITY
+//	Enter any needed parameters: READ, WRITE.
+type Sensitivity struct {
+	Read  bool
+	Write bool
+
+	Objectives  []Objective
+	Constraints []Constraint
+	Filter      *Filter // nil if filter is not defined
+}
+
+func (s Sensitivity) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*SENSITIVITY")
+	if s.Read {
+		fmt.Fprintf(&buf, ", READ")
+	}
+	if s.Write {
+		fmt.Fprintf(&buf, ", WRITE")
+	}
+	fmt.Fprintf(&buf, "\n")
+	return buf.String()
+}
+
+// criteria return objective, constraints and filter of step
+func (s Sensitivity) criteria() string {
+	var buf bytes.Buffer
+	writeObjectives(&buf, s.Objectives)
+	writeConstraints(&buf, s.Constraints)
+	if s.Filter != nil {
+		fmt.Fprintf(&buf, "%s", s.Filter)
+	}
+	return buf.String()
+}
+
+// writeObjectives write objectives in one *OBJECTIVE block
+func writeObjectives(w io.Writer, objs []Objective) {
+	if len(objs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "*OBJECTIVE\n")
+	for _, o := range objs {
+		fmt.Fprintf(w, "%s", o.line())
+	}
+}
+
+// writeConstraints write constraints in one *CONSTRAINT block
+func writeConstraints(w io.Writer, cs []Constraint) {
+	if len(cs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "*CONSTRAINT\n")
+	for _, c := range cs {
+		fmt.Fprintf(w, "%s, %s, %s, %s, %s\n", c.Type, c.Set, c.Relation,
+			efmt.Sprint(c.Relative), efmt.Sprint(c.Absolute))
+	}
+}
+
+func (s *Step) parseSensitivity(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SENSITIVITY") {
+		return false, nil
+	}
+	for _, part := range fields(block[0])[1:] {
+		key, _ := keyValue(part)
+		switch key {
+		case "READ":
+			s.Sensitivity.Read = true
+		case "WRITE":
+			s.Sensitivity.Write = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid sensitivity parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 1 {
+		err = fmt.Errorf("sensitivity have not data lines")
+		return
+	}
+	s.IsSensitivity = true
+	return true, nil
+}
+
+// FeasibleDirection is step procedure of keyword *FEASIBLE DIRECTION.
+//
+// Example:
+//
+//	*FEASIBLE DIRECTION
+//	1.
+//
+// First line:
+//
+//	*FEASIBLE DIRECTION
+//
+// Second line (optional):
+//
+//	Maximum step size of design variables.
+type FeasibleDirection struct {
+	MaxStep float64
+}
+
+func (fd FeasibleDirection) String() string {
+	if fd.MaxStep == 0 {
+		return "*FEASIBLE DIRECTION\n"
+	}
+	return fmt.Sprintf("*FEASIBLE DIRECTION\n%s\n", efmt.Sprint(fd.MaxStep))
+}
+
+func (s *Step) parseFeasibleDirection(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*FEASIBLE DIRECTION") {
+		return false, nil
+	}
+	switch len(block) {
+	case 1:
+	case 2:
+		s.FeasibleDirection.MaxStep, err = parseFloat(fields(block[1])[0])
+		if err != nil {
+			return
+		}
+	default:
+		err = fmt.Errorf("feasible direction must have maximal one data line")
+		return
+	}
+	s.IsFeasibleDirection = true
+	return true, nil
+}
@@ -0,0 +1,510 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Types of local coordinate system
+const (
+	Rectangular = "RECTANGULAR"
+	Cylindrical = "CYLINDRICAL"
+)
+
+// Orientation
+//
+// Examples:
+//
+//	*ORIENTATION,NAME=OR1,SYSTEM=RECTANGULAR
+//	1.,0.,0.,0.,1.,0.
+//
+//	*ORIENTATION,NAME=OR1,SYSTEM=CYLINDRICAL
+//	0.,0.,0.,0.,0.,1.
+//
+// First line:
+//
+//	*ORIENTATION
+//	Enter the required parameter NAME, and the optional parameters
+//	SYSTEM (default RECTANGULAR) and DEFINITION.
+//
+// Second line:
+//
+//	X-coordinate of point a.
+//	Y-coordinate of point a.
+//	Z-coordinate of point a.
+//	X-coordinate of point b.
+//	Y-coordinate of point b.
+//	Z-coordinate of point b.
+//
+// or the name of *DISTRIBUTION.
+//
+// Third line (optional):
+//
+//	Local axis about which an additional rotation is to be performed (1, 2 or 3).
+//	Angle of rotation in degrees.
+//
+// For rectangular system point a is on the local x-axis and point b is
+// in the local xy-plane. For cylindrical system points a and b are on
+// the axis of cylinder; local x is radial, local y is tangential and
+// local z is axial direction.
+type Orientation struct {
+	Name         string
+	System       string // RECTANGULAR or CYLINDRICAL
+	Definition   string // COORDINATES
+	Distribution string
+	A, B         [3]float64
+	RotationAxis int
+	Angle        float64 // degrees
+}
+
+func (o Orientation) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*ORIENTATION, NAME=%s", o.Name)
+	if o.System != "" {
+		fmt.Fprintf(&buf, ", SYSTEM=%s", o.System)
+	}
+	if o.Definition != "" {
+		fmt.Fprintf(&buf, ", DEFINITION=%s", o.Definition)
+	}
+	fmt.Fprintf(&buf, "\n")
+	if o.Distribution != "" {
+		fmt.Fprintf(&buf, "%s\n", o.Distribution)
+	} else {
+		fmt.Fprintf(&buf, "%s\n", pointsList(o.A, o.B))
+	}
+	if 0 < o.RotationAxis {
+		fmt.Fprintf(&buf, "%d, %s\n", o.RotationAxis, efmt.Sprint(o.Angle))
+	}
+	return buf.String()
+}
+
+// pointsList return coordinates of points a and b separated by comma
+func pointsList(a, b [3]float64) string {
+	var buf bytes.Buffer
+	for i, v := range append(a[:], b[:]...) {
+		if 0 < i {
+			fmt.Fprintf(&buf, ", ")
+		}
+		fmt.Fprintf(&buf, "%s", efmt.Sprint(v))
+	}
+	return buf.String()
+}
+
+// parsePoints parse coordinates of points a and b
+func parsePoints(line string) (a, b [3]float64, err error) {
+	vs, err := parseFloats(line)
+	if err != nil {
+		return
+	}
+	if len(vs) < 6 {
+		err = fmt.Errorf("not enough coordinates: %s", line)
+		return
+	}
+	copy(a[:], vs[:3])
+	copy(b[:], vs[3:6])
+	return
+}
+
+func (f *Model) parseOrientation(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*ORIENTATION") {
+		return false, nil
+	}
+	var o Orientation
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NAME":
+			o.Name = value
+		case "SYSTEM":
+			o.System = value
+		case "DEFINITION":
+			o.Definition = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid orientation parameter: %s", part)
+			return
+		}
+	}
+	if len(block) < 2 || 3 < len(block) {
+		err = fmt.Errorf("not valid orientation %s", o.Name)
+		return
+	}
+	if fs := fields(block[1]); len(fs) == 1 {
+		o.Distribution = fs[0]
+	} else {
+		o.A, o.B, err = parsePoints(block[1])
+		if err != nil {
+			return
+		}
+	}
+	if len(block) == 3 {
+		fs := fields(block[2])
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid orientation rotation: %s", block[2])
+			return
+		}
+		o.RotationAxis, err = parseInt(fs[0])
+		if err != nil {
+			return
+		}
+		o.Angle, err = parseFloat(fs[1])
+		if err != nil {
+			return
+		}
+	}
+	f.Orientations = append(f.Orientations, o)
+	return true, nil
+}
+
+// Rotation return rotation matrix of local coordinate system at point p.
+// Rows of matrix are unit vectors of local axes in global coordinates:
+//
+//	local  = R * global
+//	global = R^T * local
+func (o Orientation) Rotation(p [3]float64) (r [3][3]float64, err error) {
+	if o.Distribution != "" {
+		err = fmt.Errorf("orientation %s is defined by distribution %s",
+			o.Name, o.Distribution)
+		return
+	}
+	r, err = localAxes(o.System, o.A, o.B, p)
+	if err != nil {
+		err = fmt.Errorf("orientation %s: %v", o.Name, err)
+		return
+	}
+	if o.RotationAxis == 0 {
+		return
+	}
+	if o.RotationAxis < 1 || 3 < o.RotationAxis {
+		err = fmt.Errorf("orientation %s: not valid rotation axis %d",
+			o.Name, o.RotationAxis)
+		return
+	}
+	// additional rotation about local axis
+	i := o.RotationAxis - 1
+	j, k := (i+1)%3, (i+2)%3
+	sin, cos := math.Sincos(o.Angle * math.Pi / 180.0)
+	var rj, rk [3]float64
+	for c := 0; c < 3; c++ {
+		rj[c] = cos*r[j][c] + sin*r[k][c]
+		rk[c] = -sin*r[j][c] + cos*r[k][c]
+	}
+	r[j], r[k] = rj, rk
+	return
+}
+
+// localAxes return rotation matrix of local system defined by points a and b
+func localAxes(system string, a, b, p [3]float64) (r [3][3]float64, err error) {
+	unit := func(v [3]float64) ([3]float64, error) {
+		l := norm(v)
+		if l == 0 {
+			return v, fmt.Errorf("zero length vector")
+		}
+		return [3]float64{v[0] / l, v[1] / l, v[2] / l}, nil
+	}
+	switch system {
+	case "", Rectangular:
+		if r[0], err = unit(a); err != nil {
+			return
+		}
+		if r[2], err = unit(cross(a, b)); err != nil {
+			return
+		}
+		r[1] = cross(r[2], r[0])
+	case Cylindrical:
+		if r[2], err = unit(sub(b, a)); err != nil {
+			return
+		}
+		ap := sub(p, a)
+		h := dot(ap, r[2])
+		radial := [3]float64{ap[0] - h*r[2][0], ap[1] - h*r[2][1], ap[2] - h*r[2][2]}
+		if r[0], err = unit(radial); err != nil {
+			err = fmt.Errorf("point is on axis of cylindrical system")
+			return
+		}
+		r[1] = cross(r[2], r[0])
+	default:
+		err = fmt.Errorf("not valid system: %s", system)
+	}
+	return
+}
+
+// Transform
+//
+// Example:
+//
+//	*TRANSFORM,NSET=NALL,TYPE=C
+//	.5,.5,0.,.5,.5,8.
+//
+// First line:
+//
+//	*TRANSFORM
+//	Enter the required parameter NSET and the optional parameter TYPE
+//	(R for rectangular, default; C for cylindrical).
+//
+// Second line:
+//
+//	Coordinates of points a and b.
+//
+// Degrees of freedom of nodes in node set for *BOUNDARY and *CLOAD
+// are defined in local coordinate system.
+type Transform struct {
+	Nset string
+	Type string // R or C
+	A, B [3]float64
+}
+
+func (t Transform) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*TRANSFORM, NSET=%s", t.Nset)
+	if t.Type != "" {
+		fmt.Fprintf(&buf, ", TYPE=%s", t.Type)
+	}
+	fmt.Fprintf(&buf, "\n%s\n", pointsList(t.A, t.B))
+	return buf.String()
+}
+
+func (f *Model) parseTransform(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*TRANSFORM") {
+		return false, nil
+	}
+	var t Transform
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NSET":
+			t.Nset = value
+		case "TYPE":
+			t.Type = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid transform parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid transform %s", t.Nset)
+		return
+	}
+	t.A, t.B, err = parsePoints(block[1])
+	if err != nil {
+		return
+	}
+	f.Transforms = append(f.Transforms, t)
+	return true, nil
+}
+
+// Rotation return rotation matrix of local coordinate system at point p.
+// See Orientation.Rotation.
+func (t Transform) Rotation(p [3]float64) (r [3][3]float64, err error) {
+	system := Rectangular
+	switch t.Type {
+	case "", "R":
+	case "C":
+		system = Cylindrical
+	default:
+		err = fmt.Errorf("not valid transform type: %s", t.Type)
+		return
+	}
+	return localAxes(system, t.A, t.B, p)
+}
+
+// NodeRotation return rotation matrix of node defined by *TRANSFORM.
+// Identity matrix is returned for nodes without transformation.
+// If node is in several transformations, then last is used.
+func (f Model) NodeRotation(node int) (r [3][3]float64, err error) {
+	r = [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	var coord [3]float64
+	found := false
+	for _, n := range f.Nodes {
+		if n.Index == node {
+			coord, found = n.Coord, true
+		}
+	}
+	if !found {
+		err = fmt.Errorf("not found node %d", node)
+		return
+	}
+	for i := len(f.Transforms) - 1; 0 <= i; i-- {
+		var nodes []int
+		nodes, err = f.NsetIndexes(f.Transforms[i].Nset)
+		if err != nil {
+			return
+		}
+		for _, n := range nodes {
+			if n == node {
+				return f.Transforms[i].Rotation(coord)
+			}
+		}
+	}
+	return
+}
+
+// toGlobal return vector in global coordinates for vector
+// in local coordinates
+func toGlobal(r [3][3]float64, local [3]float64) (global [3]float64) {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			global[i] += r[j][i] * local[j]
+		}
+	}
+	return
+}
+
+// GlobalCload return global components of concentrated load for each
+// node of load position. Components 1-3 are forces and components 4-6
+// are moments.
+func (f Model) GlobalCload(load Cload) (forces map[int][6]float64, err error) {
+	if load.Direction < 1 || 6 < load.Direction {
+		err = fmt.Errorf("not valid direction of load: %d", load.Direction)
+		return
+	}
+	nodes, err := f.nodeLocation(load.Position)
+	if err != nil {
+		return
+	}
+	forces = map[int][6]float64{}
+	for _, n := range nodes {
+		var r [3][3]float64
+		r, err = f.NodeRotation(n)
+		if err != nil {
+			return
+		}
+		var local [3]float64
+		offset := 0
+		if 3 < load.Direction {
+			offset = 3
+		}
+		local[load.Direction-1-offset] = load.Value
+		global := toGlobal(r, local)
+		v := forces[n]
+		for i := range global {
+			v[i+offset] += global[i]
+		}
+		forces[n] = v
+	}
+	return
+}
+
+// GlobalConstraint - constraint of node in global coordinates:
+// displacement (or rotation) along Direction equal Value
+type GlobalConstraint struct {
+	Node      int
+	Rotation  bool
+	Direction [3]float64
+	Value     float64
+}
+
+// GlobalBoundary return constraints of boundary in global coordinates.
+// Only degrees of freedom 1-6 are converted.
+func (f Model) GlobalBoundary(b Boundary) (cs []GlobalConstraint, err error) {
+	nodes, err := f.nodeLocation(b.LoadLocation)
+	if err != nil {
+		return
+	}
+	for _, n := range nodes {
+		var r [3][3]float64
+		r, err = f.NodeRotation(n)
+		if err != nil {
+			return
+		}
+		for _, dof := range b.Dofs() {
+			if dof < 1 || 6 < dof {
+				continue
+			}
+			c := GlobalConstraint{Node: n, Rotation: 3 < dof, Value: b.Factor}
+			c.Direction = r[(dof-1)%3]
+			cs = append(cs, c)
+		}
+	}
+	return
+}
+
+// RotateTensor return symmetric tensor in local coordinates for tensor
+// in global coordinates. Order of components: xx, yy, zz, xy, xz, yz.
+func RotateTensor(r [3][3]float64, v [6]float64) (local [6]float64) {
+	t := [3][3]float64{
+		{v[0], v[3], v[4]},
+		{v[3], v[1], v[5]},
+		{v[4], v[5], v[2]},
+	}
+	var res [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				for l := 0; l < 3; l++ {
+					res[i][j] += r[i][k] * t[k][l] * r[j][l]
+				}
+			}
+		}
+	}
+	return [6]float64{res[0][0], res[1][1], res[2][2], res[0][1], res[0][2], res[1][2]}
+}
+
+// LocalStress return stress in local coordinate system of orientation
+// of solid section for element. For cylindrical system the center of
+// element is used.
+func (f Model) LocalStress(s Stress) (local Stress, err error) {
+	local = s
+	elem := s.Node // element number in stress output
+	var el Element
+	found := false
+	for _, e := range f.Elements {
+		if e.Index == elem {
+			el, found = e, true
+		}
+	}
+	if !found {
+		err = fmt.Errorf("not found element %d", elem)
+		return
+	}
+	var orientation string
+	for _, ss := range f.SolidSections {
+		if ss.Orientation == "" {
+			continue
+		}
+		var els []int
+		els, err = f.ElsetIndexes(ss.Elset)
+		if err != nil {
+			return
+		}
+		for _, e := range els {
+			if e == elem {
+				orientation = ss.Orientation
+			}
+		}
+	}
+	if orientation == "" {
+		return
+	}
+	var o Orientation
+	found = false
+	for _, or := range f.Orientations {
+		if or.Name == orientation {
+			o, found = or, true
+		}
+	}
+	if !found {
+		err = fmt.Errorf("not found orientation %s", orientation)
+		return
+	}
+	coords := f.nodeCoordinates()
+	var center [3]float64
+	for _, n := range el.Nodes {
+		c := coords[n]
+		for i := range center {
+			center[i] += c[i] / float64(len(el.Nodes))
+		}
+	}
+	r, err := o.Rotation(center)
+	if err != nil {
+		return
+	}
+	local.Values = RotateTensor(r, s.Values)
+	return
+}
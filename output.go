@@ -0,0 +1,363 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// OutputVariable is key of output request, for example: U, RF, S.
+type OutputVariable string
+
+// Nodal output variables
+const (
+	OutputU    OutputVariable = "U"    // displacements
+	OutputRF   OutputVariable = "RF"   // external forces
+	OutputNT   OutputVariable = "NT"   // structural temperatures
+	OutputTS   OutputVariable = "TS"   // structural temperatures, same as NT
+	OutputV    OutputVariable = "V"    // velocities
+	OutputPU   OutputVariable = "PU"   // displacements: magnitude and phase
+	OutputPN   OutputVariable = "PN"   // network pressures
+	OutputPS   OutputVariable = "PS"   // static pressures
+	OutputPT   OutputVariable = "PT"   // total pressures
+	OutputTT   OutputVariable = "TT"   // total temperatures
+	OutputMF   OutputVariable = "MF"   // mass flows
+	OutputVF   OutputVariable = "VF"   // fluid velocities
+	OutputPSF  OutputVariable = "PSF"  // static fluid pressures
+	OutputPTF  OutputVariable = "PTF"  // total fluid pressures
+	OutputTSF  OutputVariable = "TSF"  // static fluid temperatures
+	OutputTTF  OutputVariable = "TTF"  // total fluid temperatures
+	OutputMACH OutputVariable = "MACH" // Mach numbers
+	OutputCP   OutputVariable = "CP"   // pressure coefficients
+	OutputTURB OutputVariable = "TURB" // turbulence parameters
+	OutputDEPF OutputVariable = "DEPF" // fluid depths
+	OutputDEPT OutputVariable = "DEPT" // fluid depths
+	OutputHCRI OutputVariable = "HCRI" // critical depths
+	OutputRFL  OutputVariable = "RFL"  // external concentrated heat sources
+	OutputMAXU OutputVariable = "MAXU" // maximum displacements
+	OutputPRF  OutputVariable = "PRF"  // external forces: magnitude and phase
+	OutputPOT  OutputVariable = "POT"  // electrical potential
+	OutputSEN  OutputVariable = "SEN"  // sensitivities
+)
+
+// Element output variables
+const (
+	OutputS     OutputVariable = "S"     // stresses
+	OutputE     OutputVariable = "E"     // total strains
+	OutputME    OutputVariable = "ME"    // mechanical strains
+	OutputPE    OutputVariable = "PE"    // plastic strains
+	OutputPEEQ  OutputVariable = "PEEQ"  // equivalent plastic strain
+	OutputCEEQ  OutputVariable = "CEEQ"  // equivalent creep strain
+	OutputENER  OutputVariable = "ENER"  // energy density
+	OutputSDV   OutputVariable = "SDV"   // internal state variables
+	OutputHFL   OutputVariable = "HFL"   // heat flux
+	OutputHFLF  OutputVariable = "HFLF"  // heat flux in fluid
+	OutputSF    OutputVariable = "SF"    // total stresses in fluid
+	OutputSVF   OutputVariable = "SVF"   // viscous stresses in fluid
+	OutputTHE   OutputVariable = "THE"   // thermal strains
+	OutputZZS   OutputVariable = "ZZS"   // Zienkiewicz-Zhu improved stresses
+	OutputERR   OutputVariable = "ERR"   // error estimator
+	OutputHER   OutputVariable = "HER"   // heat error estimator
+	OutputPHS   OutputVariable = "PHS"   // stresses: magnitude and phase
+	OutputMAXS  OutputVariable = "MAXS"  // maximum stresses
+	OutputMAXE  OutputVariable = "MAXE"  // maximum strains
+	OutputNOE   OutputVariable = "NOE"   // nodal values of elements
+	OutputECD   OutputVariable = "ECD"   // electrical current density
+	OutputEMFB  OutputVariable = "EMFB"  // magnetic field
+	OutputEMFE  OutputVariable = "EMFE"  // electric field
+	OutputEVOL  OutputVariable = "EVOL"  // element volume
+	OutputEMAS  OutputVariable = "EMAS"  // element mass
+	OutputELSE  OutputVariable = "ELSE"  // element internal energy
+	OutputELKE  OutputVariable = "ELKE"  // element kinetic energy
+	OutputEBHE  OutputVariable = "EBHE"  // element body heating
+	OutputCOORD OutputVariable = "COORD" // coordinates of integration points
+	OutputCENT  OutputVariable = "CENT"  // coordinates of element centers
+)
+
+// Contact output variables
+const (
+	OutputCDIS OutputVariable = "CDIS" // relative contact displacements
+	OutputCSTR OutputVariable = "CSTR" // contact stresses
+	OutputCELS OutputVariable = "CELS" // contact energy
+	OutputCNUM OutputVariable = "CNUM" // number of contact elements
+	OutputCF   OutputVariable = "CF"   // contact forces
+	OutputCFN  OutputVariable = "CFN"  // normal contact forces
+	OutputCFS  OutputVariable = "CFS"  // shear contact forces
+)
+
+// Section output variables
+const (
+	OutputSOF    OutputVariable = "SOF"    // section forces
+	OutputSOM    OutputVariable = "SOM"    // section moments
+	OutputSOAREA OutputVariable = "SOAREA" // section area and center
+	OutputDRAG   OutputVariable = "DRAG"   // drag stresses
+	OutputFLUX   OutputVariable = "FLUX"   // heat flux through section
+)
+
+// outputKind is bit mask of output request keywords
+type outputKind int
+
+const (
+	outputNode    outputKind = 1 << iota // *NODE FILE, *NODE PRINT
+	outputElement                        // *EL FILE, *EL PRINT, *ELEMENT OUTPUT
+	outputContact                        // *CONTACT FILE, *CONTACT PRINT
+	outputSection                        // *SECTION PRINT
+)
+
+// outputVariable is description of output variable
+type outputVariable struct {
+	kind       outputKind
+	procedures []Procedure // empty for all procedures
+}
+
+var (
+	heatProcedures      = []Procedure{ProcedureHeatTransfer, ProcedureCoupled}
+	frequencyProcedures = []Procedure{ProcedureFrequency, ProcedureSteadyStateDynamics}
+)
+
+var outputVariables = map[OutputVariable]outputVariable{
+	OutputU:    {kind: outputNode},
+	OutputRF:   {kind: outputNode},
+	OutputNT:   {kind: outputNode},
+	OutputTS:   {kind: outputNode},
+	OutputV:    {kind: outputNode},
+	OutputPU:   {kind: outputNode, procedures: frequencyProcedures},
+	OutputPN:   {kind: outputNode},
+	OutputPS:   {kind: outputNode},
+	OutputPT:   {kind: outputNode},
+	OutputTT:   {kind: outputNode},
+	OutputMF:   {kind: outputNode | outputElement},
+	OutputVF:   {kind: outputNode},
+	OutputPSF:  {kind: outputNode},
+	OutputPTF:  {kind: outputNode},
+	OutputTSF:  {kind: outputNode},
+	OutputTTF:  {kind: outputNode},
+	OutputMACH: {kind: outputNode},
+	OutputCP:   {kind: outputNode},
+	OutputTURB: {kind: outputNode},
+	OutputDEPF: {kind: outputNode},
+	OutputDEPT: {kind: outputNode},
+	OutputHCRI: {kind: outputNode},
+	OutputRFL:  {kind: outputNode, procedures: heatProcedures},
+	OutputMAXU: {kind: outputNode, procedures: []Procedure{ProcedureFrequency}},
+	OutputPRF:  {kind: outputNode, procedures: frequencyProcedures},
+	OutputPOT:  {kind: outputNode},
+	OutputSEN:  {kind: outputNode, procedures: []Procedure{ProcedureSensitivity}},
+
+	OutputS:     {kind: outputElement},
+	OutputE:     {kind: outputElement},
+	OutputME:    {kind: outputElement},
+	OutputPE:    {kind: outputElement},
+	OutputPEEQ:  {kind: outputElement},
+	OutputCEEQ:  {kind: outputElement},
+	OutputENER:  {kind: outputElement},
+	OutputSDV:   {kind: outputElement},
+	OutputHFL:   {kind: outputElement, procedures: heatProcedures},
+	OutputHFLF:  {kind: outputElement},
+	OutputSF:    {kind: outputElement},
+	OutputSVF:   {kind: outputElement},
+	OutputTHE:   {kind: outputElement},
+	OutputZZS:   {kind: outputElement},
+	OutputERR:   {kind: outputElement},
+	OutputHER:   {kind: outputElement, procedures: heatProcedures},
+	OutputPHS:   {kind: outputElement, procedures: frequencyProcedures},
+	OutputMAXS:  {kind: outputElement, procedures: []Procedure{ProcedureFrequency}},
+	OutputMAXE:  {kind: outputElement, procedures: []Procedure{ProcedureFrequency}},
+	OutputNOE:   {kind: outputElement},
+	OutputECD:   {kind: outputElement},
+	OutputEMFB:  {kind: outputElement},
+	OutputEMFE:  {kind: outputElement},
+	OutputEVOL:  {kind: outputElement},
+	OutputEMAS:  {kind: outputElement},
+	OutputELSE:  {kind: outputElement},
+	OutputELKE:  {kind: outputElement},
+	OutputEBHE:  {kind: outputElement},
+	OutputCOORD: {kind: outputElement},
+	OutputCENT:  {kind: outputElement},
+
+	OutputCDIS: {kind: outputContact},
+	OutputCSTR: {kind: outputContact},
+	OutputCELS: {kind: outputContact},
+	OutputCNUM: {kind: outputContact},
+	OutputCF:   {kind: outputContact},
+	OutputCFN:  {kind: outputContact},
+	OutputCFS:  {kind: outputContact},
+
+	OutputSOF:    {kind: outputSection},
+	OutputSOM:    {kind: outputSection},
+	OutputSOAREA: {kind: outputSection},
+	OutputDRAG:   {kind: outputSection},
+	OutputFLUX:   {kind: outputSection},
+}
+
+// lookup return description of output variable. As in CalculiX, only
+// first 4 characters of long keys are significant, so that CDISP is
+// same as CDIS.
+func (v OutputVariable) lookup() (o outputVariable, ok bool) {
+	if o, ok = outputVariables[v]; ok {
+		return
+	}
+	if len(v) < 4 {
+		return
+	}
+	for key, o := range outputVariables {
+		if 4 <= len(key) && key[:4] == v[:4] {
+			return o, true
+		}
+	}
+	return
+}
+
+// outputList return output variables separated by comma
+func outputList(vs []OutputVariable) string {
+	list := make([]string, len(vs))
+	for i, v := range vs {
+		list[i] = string(v)
+	}
+	return strings.Join(list, ", ")
+}
+
+// SectionPrint
+//
+// Example:
+//
+//	*SECTION PRINT,SURFACE=S1,NAME=SP1
+//	SOF,SOM
+//
+// First line:
+//
+//	*SECTION PRINT
+//	Enter the parameters SURFACE and NAME and their values and, if
+//	necessary, the parameters FREQUENCYF and TIME POINTS.
+//
+// Second line:
+//
+//	Output variables (SOF, SOM, SOAREA, DRAG, FLUX).
+type SectionPrint struct {
+	Name         string
+	Surface      string
+	FrequencyF   int
+	IsFrequencyF bool // zero is valid value of FREQUENCYF
+	TimePoints   string
+	Options      []OutputVariable
+}
+
+func (sp SectionPrint) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*SECTION PRINT, SURFACE=%s, NAME=%s", sp.Surface, sp.Name)
+	if sp.IsFrequencyF || sp.FrequencyF != 0 {
+		fmt.Fprintf(&buf, ", FREQUENCYF=%d", sp.FrequencyF)
+	}
+	if sp.TimePoints != "" {
+		fmt.Fprintf(&buf, ", TIME POINTS=%s", sp.TimePoints)
+	}
+	fmt.Fprintf(&buf, "\n")
+	if 0 < len(sp.Options) {
+		fmt.Fprintf(&buf, "%s\n", outputList(sp.Options))
+	}
+	return buf.String()
+}
+
+func (s *Step) parseSectionPrint(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SECTION PRINT") {
+		return false, nil
+	}
+	var sp SectionPrint
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NAME":
+			sp.Name = value
+		case "SURFACE":
+			sp.Surface = value
+		case "FREQUENCYF", "FREQUENCY":
+			sp.FrequencyF, err = parseInt(value)
+			sp.IsFrequencyF = true
+		case "TIME POINTS", "TIMEPOINTS":
+			sp.TimePoints = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid section print parameter: %s", part)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if sp.Name == "" || sp.Surface == "" {
+		err = fmt.Errorf("section print must have parameters SURFACE and NAME")
+		return
+	}
+	for _, line := range block[1:] {
+		for _, field := range fields(line) {
+			if field == "" {
+				continue
+			}
+			sp.Options = append(sp.Options, OutputVariable(field))
+		}
+	}
+	s.SectionPrints = append(s.SectionPrints, sp)
+	return true, nil
+}
+
+// checkOutput return error if output variables are not valid for kind of
+// output request or for step procedure. Procedure is not checked for
+// step without procedure.
+func checkOutput(vs []OutputVariable, kind outputKind, procedure Procedure) error {
+	for _, v := range vs {
+		o, ok := v.lookup()
+		if !ok {
+			return fmt.Errorf("unknown output variable %s", v)
+		}
+		if o.kind&kind == 0 {
+			return fmt.Errorf("output variable %s is not valid for that request", v)
+		}
+		if len(o.procedures) == 0 || procedure == "" {
+			continue
+		}
+		found := false
+		for _, p := range o.procedures {
+			if p == procedure {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("output variable %s is not valid for procedure %s", v, procedure)
+		}
+	}
+	return nil
+}
+
+// CheckOutputs return error if any output request of steps has output
+// variable that is unknown, not valid for output request keyword or
+// not valid for step procedure.
+func (f Model) CheckOutputs() error {
+	for i, s := range f.Steps {
+		procedure := s.Procedure()
+		for _, request := range []struct {
+			prefix string
+			kind   outputKind
+			prints []Print
+		}{
+			{prefix: "*NODE FILE", kind: outputNode, prints: s.NodeFiles},
+			{prefix: "*NODE PRINT", kind: outputNode, prints: s.NodePrints},
+			{prefix: "*EL FILE", kind: outputElement, prints: s.ElFiles},
+			{prefix: "*EL PRINT", kind: outputElement, prints: s.ElPrints},
+			{prefix: "*ELEMENT OUTPUT", kind: outputElement, prints: s.ElementOutputs},
+			{prefix: "*CONTACT FILE", kind: outputContact, prints: s.ContactFiles},
+			{prefix: "*CONTACT PRINT", kind: outputContact, prints: s.ContactPrints},
+		} {
+			for _, pr := range request.prints {
+				if err := checkOutput(pr.Options, request.kind, procedure); err != nil {
+					return fmt.Errorf("step %d: %s: %v", i+1, request.prefix, err)
+				}
+			}
+		}
+		for _, sp := range s.SectionPrints {
+			if err := checkOutput(sp.Options, outputSection, procedure); err != nil {
+				return fmt.Errorf("step %d: *SECTION PRINT: %v", i+1, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,619 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Frequency
+//
+// Examples:
+//
+//	*FREQUENCY
+//	10
+//
+//	*FREQUENCY,SOLVER=ARPACK,STORAGE=YES
+//	10,0.01
+//
+// First line:
+//
+//	*FREQUENCY
+//	Enter any needed parameters and their values:
+//	SOLVER, STORAGE (YES or NO), GLOBAL (YES or NO),
+//	CYCMPC (ACTIVE or INACTIVE).
+//
+// Second line:
+//
+//	Number of eigenfrequencies desired.
+//	Lower value of requested eigenfrequency range (in cycles/time; default 0).
+//	Upper value of requested eigenfrequency range (in cycles/time; default infinity).
+type Frequency struct {
+	Solver  string
+	Storage string
+	Global  string
+	Cycmpc  string
+
+	Number     int
+	LowerBound float64
+	UpperBound float64
+}
+
+func (fr Frequency) String() string {
+	if fr.Number <= 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*FREQUENCY")
+	for _, p := range [...]struct{ name, value string }{
+		{"SOLVER", fr.Solver},
+		{"STORAGE", fr.Storage},
+		{"GLOBAL", fr.Global},
+		{"CYCMPC", fr.Cycmpc},
+	} {
+		if p.value != "" {
+			fmt.Fprintf(&buf, ", %s=%s", p.name, p.value)
+		}
+	}
+	fmt.Fprintf(&buf, "\n%d", fr.Number)
+	if fr.LowerBound != 0 || fr.UpperBound != 0 {
+		fmt.Fprintf(&buf, ", %s", efmt.Sprint(fr.LowerBound))
+	}
+	if fr.UpperBound != 0 {
+		fmt.Fprintf(&buf, ", %s", efmt.Sprint(fr.UpperBound))
+	}
+	fmt.Fprintf(&buf, "\n")
+	return buf.String()
+}
+
+func (s *Step) parseFrequency(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*FREQUENCY") {
+		return false, nil
+	}
+	var fr Frequency
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "SOLVER":
+			fr.Solver = value
+		case "STORAGE":
+			fr.Storage = value
+		case "GLOBAL":
+			fr.Global = value
+		case "CYCMPC":
+			fr.Cycmpc = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid frequency parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid block: %s", strings.Join(block, "\n"))
+		return
+	}
+	for i, f := range fields(block[1]) {
+		if f == "" {
+			continue
+		}
+		switch i {
+		case 0:
+			fr.Number, err = parseInt(f)
+		case 1:
+			fr.LowerBound, err = parseFloat(f)
+		case 2:
+			fr.UpperBound, err = parseFloat(f)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if fr.Number <= 0 {
+		err = fmt.Errorf("not valid number of eigenfrequencies: %s", block[1])
+		return
+	}
+	s.Frequency = fr
+	return true, nil
+}
+
+// Dynamic
+//
+// Examples:
+//
+//	*DYNAMIC,DIRECT
+//	1.E-8,2.E-7
+//
+//	*DYNAMIC,EXPLICIT
+//	1.E-7,1.E-5
+//
+// First line:
+//
+//	*DYNAMIC
+//	Enter any needed parameters and their values:
+//	DIRECT, EXPLICIT, ALPHA (default -0.05), SOLVER.
+//
+// Second line:
+//
+//	Initial time increment.
+//	Time period of the step.
+//	Minimum time increment allowed (only active if DIRECT is not specified).
+//	Maximum time increment allowed (only active if DIRECT is not specified).
+type Dynamic struct {
+	Direct   bool
+	Explicit bool
+	IsAlpha  bool // zero is valid value of alpha
+	Alpha    float64
+	Solver   string
+
+	TimeInc    float64
+	TimePeriod float64
+	MinInc     float64
+	MaxInc     float64
+}
+
+func (d Dynamic) String() string {
+	if d.TimeInc == 0 && d.TimePeriod == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*DYNAMIC")
+	if d.Direct {
+		fmt.Fprintf(&buf, ", DIRECT")
+	}
+	if d.Explicit {
+		fmt.Fprintf(&buf, ", EXPLICIT")
+	}
+	if d.IsAlpha || d.Alpha != 0 {
+		fmt.Fprintf(&buf, ", ALPHA=%s", efmt.Sprint(d.Alpha))
+	}
+	if d.Solver != "" {
+		fmt.Fprintf(&buf, ", SOLVER=%s", d.Solver)
+	}
+	fmt.Fprintf(&buf, "\n%s\n", floatList(d.TimeInc, d.TimePeriod, d.MinInc, d.MaxInc))
+	return buf.String()
+}
+
+func (s *Step) parseDynamic(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*DYNAMIC") {
+		return false, nil
+	}
+	var d Dynamic
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "DIRECT":
+			d.Direct = true
+		case "EXPLICIT":
+			d.Explicit = true
+		case "ALPHA":
+			d.IsAlpha = true
+			d.Alpha, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		case "SOLVER":
+			d.Solver = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid dynamic parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid block: %s", strings.Join(block, "\n"))
+		return
+	}
+	vs, err := parseFloats(block[1])
+	if err != nil {
+		return
+	}
+	vs = append(vs, 0, 0, 0, 0)
+	d.TimeInc, d.TimePeriod, d.MinInc, d.MaxInc = vs[0], vs[1], vs[2], vs[3]
+	s.IsDynamic = true
+	s.Dynamic = d
+	return true, nil
+}
+
+// ModalDynamic
+//
+// Examples:
+//
+//	*MODAL DYNAMIC
+//	1.E-5,1.E-4
+//
+//	*MODAL DYNAMIC,STEADY STATE
+//	1.E-3,3.E-1
+//
+// First line:
+//
+//	*MODAL DYNAMIC
+//	Enter any needed parameters and their values:
+//	SOLVER, DIRECT, DELTMX, STEADY STATE.
+//
+// Second line:
+//
+//	Time increment (initial time increment for STEADY STATE).
+//	Time period of the step.
+//	Minimum time increment allowed (only for STEADY STATE).
+//	Maximum time increment allowed (only for STEADY STATE).
+type ModalDynamic struct {
+	Solver      string
+	Direct      bool
+	Deltmx      float64
+	SteadyState bool
+
+	TimeInc    float64
+	TimePeriod float64
+	MinInc     float64
+	MaxInc     float64
+}
+
+func (md ModalDynamic) String() string {
+	if md.TimeInc == 0 && md.TimePeriod == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*MODAL DYNAMIC")
+	if md.Solver != "" {
+		fmt.Fprintf(&buf, ", SOLVER=%s", md.Solver)
+	}
+	if md.Direct {
+		fmt.Fprintf(&buf, ", DIRECT")
+	}
+	if md.Deltmx != 0 {
+		fmt.Fprintf(&buf, ", DELTMX=%s", efmt.Sprint(md.Deltmx))
+	}
+	if md.SteadyState {
+		fmt.Fprintf(&buf, ", STEADY STATE")
+	}
+	fmt.Fprintf(&buf, "\n%s\n", floatList(md.TimeInc, md.TimePeriod, md.MinInc, md.MaxInc))
+	return buf.String()
+}
+
+func (s *Step) parseModalDynamic(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*MODAL DYNAMIC") {
+		return false, nil
+	}
+	var md ModalDynamic
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "SOLVER":
+			md.Solver = value
+		case "DIRECT":
+			md.Direct = true
+		case "DELTMX":
+			md.Deltmx, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		case "STEADY STATE":
+			md.SteadyState = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid modal dynamic parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid block: %s", strings.Join(block, "\n"))
+		return
+	}
+	vs, err := parseFloats(block[1])
+	if err != nil {
+		return
+	}
+	vs = append(vs, 0, 0, 0, 0)
+	md.TimeInc, md.TimePeriod, md.MinInc, md.MaxInc = vs[0], vs[1], vs[2], vs[3]
+	s.IsModalDynamic = true
+	s.ModalDynamic = md
+	return true, nil
+}
+
+// SteadyStateDynamics
+//
+// Examples:
+//
+//	*STEADY STATE DYNAMICS
+//	12000.,14000.,5
+//
+//	*STEADY STATE DYNAMICS,HARMONIC=NO
+//	12000.,14000.,3,1.,40,0.,0.8333E-4
+//
+// First line:
+//
+//	*STEADY STATE DYNAMICS
+//	Enter any needed parameters and their values:
+//	SOLVER, HARMONIC (YES or NO).
+//
+// Second line:
+//
+//	Lower bound of the frequency range (cycles/time).
+//	Upper bound of the frequency range (cycles/time).
+//	Number of data points (default 20).
+//	Bias (default 3).
+//	Number of Fourier terms (only for HARMONIC=NO, default 20).
+//	Lower bound of the time range (only for HARMONIC=NO, default 0).
+//	Upper bound of the time range (only for HARMONIC=NO, default 1/frequency).
+type SteadyStateDynamics struct {
+	Solver   string
+	Harmonic string
+
+	LowerFrequency float64
+	UpperFrequency float64
+	Points         int
+	Bias           float64
+	FourierTerms   int
+	LowerTime      float64
+	UpperTime      float64
+}
+
+func (ssd SteadyStateDynamics) String() string {
+	if ssd.LowerFrequency == 0 && ssd.UpperFrequency == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*STEADY STATE DYNAMICS")
+	if ssd.Solver != "" {
+		fmt.Fprintf(&buf, ", SOLVER=%s", ssd.Solver)
+	}
+	if ssd.Harmonic != "" {
+		fmt.Fprintf(&buf, ", HARMONIC=%s", ssd.Harmonic)
+	}
+	list := []string{
+		efmt.Sprint(ssd.LowerFrequency),
+		efmt.Sprint(ssd.UpperFrequency),
+		fmt.Sprintf("%d", ssd.Points),
+		efmt.Sprint(ssd.Bias),
+		fmt.Sprintf("%d", ssd.FourierTerms),
+		efmt.Sprint(ssd.LowerTime),
+		efmt.Sprint(ssd.UpperTime),
+	}
+	size := 2
+	for i, v := range []bool{
+		ssd.Points != 0, ssd.Bias != 0, ssd.FourierTerms != 0,
+		ssd.LowerTime != 0, ssd.UpperTime != 0,
+	} {
+		if v {
+			size = i + 3
+		}
+	}
+	fmt.Fprintf(&buf, "\n%s\n", strings.Join(list[:size], ", "))
+	return buf.String()
+}
+
+func (s *Step) parseSteadyStateDynamics(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*STEADY STATE DYNAMICS") {
+		return false, nil
+	}
+	var ssd SteadyStateDynamics
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "SOLVER":
+			ssd.Solver = value
+		case "HARMONIC":
+			ssd.Harmonic = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid steady state dynamics parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("not valid block: %s", strings.Join(block, "\n"))
+		return
+	}
+	vs, err := parseFloats(block[1])
+	if err != nil {
+		return
+	}
+	vs = append(vs, make([]float64, 7)...)
+	ssd.LowerFrequency, ssd.UpperFrequency = vs[0], vs[1]
+	ssd.Points, ssd.Bias = int(vs[2]), vs[3]
+	ssd.FourierTerms, ssd.LowerTime, ssd.UpperTime = int(vs[4]), vs[5], vs[6]
+	s.IsSteadyStateDynamics = true
+	s.SteadyStateDynamics = ssd
+	return true, nil
+}
+
+// ModalDamping
+//
+// Examples:
+//
+//	*MODAL DAMPING,RAYLEIGH
+//	,,5000.,0.
+//
+//	*MODAL DAMPING
+//	1,5,.5
+//
+// First line:
+//
+//	*MODAL DAMPING
+//	Enter the parameter RAYLEIGH, if needed.
+//
+// Second line if RAYLEIGH is specified:
+//
+//	Not used.
+//	Not used.
+//	Coefficient of the mass matrix (alpha).
+//	Coefficient of the stiffness matrix (beta).
+//
+// Following lines if RAYLEIGH is not specified:
+//
+//	Lowest mode of the range.
+//	Highest mode of the range.
+//	Damping ratio (fraction of critical damping).
+type ModalDamping struct {
+	Rayleigh bool
+	Alpha    float64
+	Beta     float64
+	Modes    []DampingRatio
+}
+
+// DampingRatio - damping ratio of range of modes
+type DampingRatio struct {
+	LowestMode  int
+	HighestMode int
+	Ratio       float64
+}
+
+func (md ModalDamping) String() string {
+	if !md.Rayleigh && len(md.Modes) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*MODAL DAMPING")
+	if md.Rayleigh {
+		fmt.Fprintf(&buf, ", RAYLEIGH\n,, %s, %s\n",
+			efmt.Sprint(md.Alpha), efmt.Sprint(md.Beta))
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "\n")
+	for _, m := range md.Modes {
+		fmt.Fprintf(&buf, "%d, %d, %s\n", m.LowestMode, m.HighestMode, efmt.Sprint(m.Ratio))
+	}
+	return buf.String()
+}
+
+func (s *Step) parseModalDamping(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*MODAL DAMPING") {
+		return false, nil
+	}
+	var md ModalDamping
+	for _, part := range fields(block[0])[1:] {
+		key, _ := keyValue(part)
+		switch key {
+		case "RAYLEIGH":
+			md.Rayleigh = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid modal damping parameter: %s", part)
+			return
+		}
+	}
+	for _, line := range block[1:] {
+		var vs []float64
+		vs, err = parseFloats(line)
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0, 0, 0)
+		if md.Rayleigh {
+			md.Alpha, md.Beta = vs[2], vs[3]
+			continue
+		}
+		md.Modes = append(md.Modes, DampingRatio{
+			LowestMode:  int(vs[0]),
+			HighestMode: int(vs[1]),
+			Ratio:       vs[2],
+		})
+	}
+	s.ModalDamping = md
+	return true, nil
+}
+
+// Damping - material damping
+//
+// Examples:
+//
+//	*DAMPING,ALPHA=500000.,BETA=0.
+//	*DAMPING,STRUCTURAL=0.03
+//
+// First and only line:
+//
+//	*DAMPING
+//	Enter the parameters ALPHA and BETA (Rayleigh damping)
+//	or STRUCTURAL (structural damping) and their values.
+type Damping struct {
+	Alpha      float64
+	Beta       float64
+	Structural float64
+}
+
+func (d Damping) String() string {
+	switch {
+	case d.Structural != 0:
+		return fmt.Sprintf("*DAMPING, STRUCTURAL=%s\n", efmt.Sprint(d.Structural))
+	case d.Alpha != 0 || d.Beta != 0:
+		return fmt.Sprintf("*DAMPING, ALPHA=%s, BETA=%s\n",
+			efmt.Sprint(d.Alpha), efmt.Sprint(d.Beta))
+	}
+	return ""
+}
+
+func (f *Model) parseDamping(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*DAMPING") {
+		return false, nil
+	}
+	d := &f.lastMaterial().Damping
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "ALPHA":
+			d.Alpha, err = parseFloat(value)
+		case "BETA":
+			d.Beta, err = parseFloat(value)
+		case "STRUCTURAL":
+			d.Structural, err = parseFloat(value)
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid damping parameter: %s", part)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return true, nil
+}
+
+// Procedure is keyword of step procedure
+type Procedure string
+
+// Step procedures
+const (
+	ProcedureStatic              Procedure = "*STATIC"
+	ProcedureFrequency           Procedure = "*FREQUENCY"
+	ProcedureBuckle              Procedure = "*BUCKLE"
+	ProcedureDynamic             Procedure = "*DYNAMIC"
+	ProcedureModalDynamic        Procedure = "*MODAL DYNAMIC"
+	ProcedureSteadyStateDynamics Procedure = "*STEADY STATE DYNAMICS"
+	ProcedureHeatTransfer        Procedure = "*HEAT TRANSFER"
+	ProcedureCoupled             Procedure = "*COUPLED TEMPERATURE-DISPLACEMENT"
+	ProcedureSensitivity         Procedure = "*SENSITIVITY"
+	ProcedureFeasibleDirection   Procedure = "*FEASIBLE DIRECTION"
+)
+
+// Procedure return procedure of step.
+// Empty procedure is returned for step without procedure.
+func (s Step) Procedure() Procedure {
+	switch {
+	case s.IsStatic:
+		return ProcedureStatic
+	case 0 < s.Frequency.Number:
+		return ProcedureFrequency
+	case 0 < s.Buckle.Number:
+		return ProcedureBuckle
+	case s.IsDynamic:
+		return ProcedureDynamic
+	case s.IsModalDynamic:
+		return ProcedureModalDynamic
+	case s.IsSteadyStateDynamics:
+		return ProcedureSteadyStateDynamics
+	case s.IsHeatTransfer:
+		return s.HeatTransfer.Keyword()
+	case s.IsSensitivity:
+		return ProcedureSensitivity
+	case s.IsFeasibleDirection:
+		return ProcedureFeasibleDirection
+	}
+	return ""
+}
@@ -0,0 +1,434 @@
+package inp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Keyword is keyword of source input file with data lines and comments
+// before it. Keywords are stored by Parse in source order.
+type Keyword struct {
+	// Lines is source lines of keyword with comments before it.
+	// Sub-keywords changing items of previous keyword, for example
+	// *ELASTIC after *MATERIAL, are part of previous keyword.
+	Lines []string
+
+	// Comments is amount of comment and empty lines before keyword
+	Comments int
+
+	// Ranges is items of model produced by keyword
+	Ranges []Range
+
+	// Keywords is keywords of step from *STEP to *END STEP with ranges
+	// of step items. Keywords is empty for keywords outside of step.
+	Keywords []Keyword
+
+	sum  [sha256.Size]byte // checksum of items just after parsing
+	rows []uint64          // checksums of item lines, nil if lines are not same as source lines
+}
+
+// Range is range of items [From:To) in group of model, for example
+// nodes of *NODE or materials of *MATERIAL. Group is name of keyword.
+type Range struct {
+	Group    string
+	From, To int
+}
+
+// isComment return true for comment or empty line
+func isComment(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, "**") || strings.HasPrefix(line, ">**")
+}
+
+// sourceChunks split source lines by keywords in same way as
+// splitByBlocks. Comments and empty lines before keyword are part of
+// keyword chunk. Comments at the end of file are returned separately.
+func sourceChunks(raw, lines []string) (chunks [][]string, comments []int, tail []string) {
+	var starts []int
+	for i, line := range lines {
+		if !isComment(line) && strings.HasPrefix(strings.TrimSpace(line), "*") {
+			starts = append(starts, i)
+		}
+	}
+	end := len(lines)
+	for 0 < end && isComment(lines[end-1]) {
+		end--
+	}
+	begins := make([]int, len(starts)+1)
+	for k, s := range starts {
+		b := s
+		for 0 < b && isComment(lines[b-1]) {
+			b--
+		}
+		if k == 0 {
+			b = 0
+		}
+		begins[k] = b
+	}
+	begins[len(starts)] = end
+	for k, s := range starts {
+		chunks = append(chunks, raw[begins[k]:begins[k+1]])
+		comments = append(comments, s-begins[k])
+	}
+	tail = raw[end:]
+	if 0 < len(tail) && tail[len(tail)-1] == "" {
+		// end of last line
+		tail = tail[:len(tail)-1]
+	}
+	return
+}
+
+// groupState is state of item group used for finding items produced by
+// keyword
+type groupState struct {
+	length int
+	text   string
+}
+
+func groupStates(groups []itemGroup) (states []groupState) {
+	states = make([]groupState, len(groups))
+	for i, g := range groups {
+		if g.length != nil {
+			states[i].length = g.length()
+			continue
+		}
+		var buf bytes.Buffer
+		g.write(&buf, 0, 1)
+		states[i].text = buf.String()
+	}
+	return
+}
+
+// changedRanges return ranges of items changed after state before
+func changedRanges(groups []itemGroup, before []groupState) (ranges []Range) {
+	after := groupStates(groups)
+	for i, g := range groups {
+		switch {
+		case g.length != nil && before[i].length < after[i].length:
+			ranges = append(ranges, Range{Group: g.name, From: before[i].length, To: after[i].length})
+		case g.length == nil && before[i].text != after[i].text:
+			ranges = append(ranges, Range{Group: g.name, From: 0, To: 1})
+		}
+	}
+	return
+}
+
+// blockRanges is ranges of items produced by block of keyword. Block
+// without ranges is part of previous keyword, if it changes items of
+// previous keyword, for example *ELASTIC after *MATERIAL. Other blocks
+// without ranges are separate keywords written as in source.
+type blockRanges struct {
+	ranges []Range
+	part   bool
+}
+
+// blockState is state of items before parsing of block
+type blockState struct {
+	groups []groupState
+	prev   []Range // ranges of previous keyword
+	text   string  // items of previous keyword
+}
+
+func newBlockState(groups []itemGroup, prev []Range) blockState {
+	return blockState{
+		groups: groupStates(groups),
+		prev:   prev,
+		text:   rangesText(groupsByName(groups), prev),
+	}
+}
+
+// changed return ranges of items changed after parsing of block
+func (s blockState) changed(groups []itemGroup) (r blockRanges) {
+	r.ranges = changedRanges(groups, s.groups)
+	r.part = len(r.ranges) == 0 && 0 < len(s.prev) &&
+		s.text != rangesText(groupsByName(groups), s.prev)
+	return
+}
+
+// addKeyword add source lines of keyword
+func (f *Model) addKeyword(lines []string, comments int, r blockRanges) {
+	f.Source = appendKeyword(f.Source, lines, comments, r)
+}
+
+func appendKeyword(ks []Keyword, lines []string, comments int, r blockRanges) []Keyword {
+	if r.part && 0 < len(ks) {
+		k := &ks[len(ks)-1]
+		k.Lines = append(k.Lines, lines...)
+		return ks
+	}
+	return append(ks, Keyword{
+		Lines:    append([]string{}, lines...),
+		Comments: comments,
+		Ranges:   r.ranges,
+	})
+}
+
+// stepKeywords return keywords of step from chunks of *STEP, keywords
+// inside of step and *END STEP. Blocks is ranges of keywords inside of
+// step.
+func stepKeywords(chunks [][]string, comments []int, blocks []blockRanges) (ks []Keyword) {
+	last := len(chunks) - 1
+	ks = appendKeyword(ks, chunks[0], comments[0], blockRanges{
+		ranges: []Range{{Group: "STEP", From: 0, To: 1}},
+	})
+	for i, r := range blocks {
+		ks = appendKeyword(ks, chunks[i+1], comments[i+1], r)
+	}
+	return appendKeyword(ks, chunks[last], comments[last], blockRanges{
+		ranges: []Range{{Group: "END STEP", From: 0, To: 1}},
+	})
+}
+
+// step return index of step with keywords inside of step
+func (k Keyword) step(steps int) (index int, ok bool) {
+	if len(k.Keywords) == 0 || len(k.Ranges) != 1 {
+		return
+	}
+	r := k.Ranges[0]
+	if r.Group != "STEP" || r.To != r.From+1 || steps <= r.From {
+		return
+	}
+	return r.From, true
+}
+
+// writeRanges write items of ranges
+func writeRanges(buf *bytes.Buffer, groups map[string]itemGroup, ranges []Range) {
+	for _, r := range ranges {
+		groups[r.Group].write(buf, r.From, r.To)
+	}
+}
+
+// rangesText return written items of ranges
+func rangesText(groups map[string]itemGroup, ranges []Range) string {
+	var buf bytes.Buffer
+	writeRanges(&buf, groups, ranges)
+	return buf.String()
+}
+
+// groupsByName return item groups by names
+func groupsByName(groups []itemGroup) map[string]itemGroup {
+	m := map[string]itemGroup{}
+	for _, g := range groups {
+		m[g.name] = g
+	}
+	return m
+}
+
+// splitLines split text into lines without end of lines
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// sumLines return checksums of lines
+func sumLines(lines []string) []uint64 {
+	sums := make([]uint64, len(lines))
+	for i, line := range lines {
+		h := fnv.New64a()
+		h.Write([]byte(line))
+		sums[i] = h.Sum64()
+	}
+	return sums
+}
+
+// firstField return first field of line in upper case
+func firstField(line string) string {
+	if index := strings.Index(line, ","); 0 <= index {
+		line = line[:index]
+	}
+	return strings.Join(strings.Fields(strings.ToUpper(line)), " ")
+}
+
+// sameRow return true if written line is line of the same item as
+// source line. Keyword lines must have the same keyword and data lines
+// must have the same first field, for example index of node.
+func sameRow(source, written string) bool {
+	source, written = strings.TrimSpace(source), strings.TrimSpace(written)
+	if strings.HasPrefix(source, "*") != strings.HasPrefix(written, "*") {
+		return false
+	}
+	a, b := firstField(source), firstField(written)
+	if a == b {
+		return true
+	}
+	va, errA := strconv.ParseFloat(a, 64)
+	vb, errB := strconv.ParseFloat(b, 64)
+	return errA == nil && errB == nil && va == vb
+}
+
+// sumRows return checksums of written lines if each written line is
+// line of the same item as source line, otherwise nil
+func sumRows(lines []string, text string) []uint64 {
+	written := splitLines(text)
+	row := 0
+	for _, line := range lines {
+		if isComment(line) {
+			continue
+		}
+		if len(written) <= row || !sameRow(line, written[row]) {
+			return nil
+		}
+		row++
+	}
+	if row != len(written) {
+		return nil
+	}
+	return sumLines(written)
+}
+
+// sumKeywords calculate checksums of items produced by keywords
+func sumKeywords(keywords []Keyword, groups map[string]itemGroup) {
+	for i := range keywords {
+		k := &keywords[i]
+		var buf bytes.Buffer
+		writeRanges(&buf, groups, k.Ranges)
+		k.sum = sha256.Sum256(buf.Bytes())
+		if len(k.Keywords) == 0 {
+			k.rows = sumRows(k.Lines, buf.String())
+		}
+	}
+}
+
+// sumSource calculate checksum of items produced by each keyword
+func (f *Model) sumSource() {
+	sumKeywords(f.Source, groupsByName(f.itemGroups(WriteOptions{})))
+	for _, k := range f.Source {
+		if index, ok := k.step(len(f.Steps)); ok {
+			sumKeywords(k.Keywords, groupsByName(f.Steps[index].itemGroups(WriteOptions{})))
+		}
+	}
+}
+
+// writeSource write keywords in source order. Keyword with changed items
+// is written with comments before it. Items added after parsing are
+// written after last keyword of the same group. Groups without keywords
+// in source are written before first step. Changed step is written by
+// keywords inside of step.
+func (f Model) writeSource(out *caseWriter, o WriteOptions) {
+	writeKeywords(out, f.Source, f.itemGroups(WriteOptions{}), f.itemGroups(o), "STEP",
+		func(k Keyword) bool {
+			index, ok := k.step(len(f.Steps))
+			if !ok {
+				return false
+			}
+			s := &f.Steps[index]
+			writeKeywords(out, k.Keywords, s.itemGroups(WriteOptions{}), s.itemGroups(o), "END STEP", nil)
+			return true
+		})
+}
+
+// writeKeywords write keywords in source order. Items of check groups
+// are compared with checksums and items of groups are written. Groups
+// without keywords are written before first keyword of group before or
+// at the end. Function changed write keyword with changed items and
+// return false if keyword must be written by items.
+func writeKeywords(out *caseWriter, keywords []Keyword, check, groups []itemGroup, before string, changed func(k Keyword) bool) {
+	checkByName := groupsByName(check)
+	byName := groupsByName(groups)
+	last := map[string]int{}
+	for i, k := range keywords {
+		for _, r := range k.Ranges {
+			last[r.Group] = i
+		}
+	}
+	uncovered := false
+	writeUncovered := func() {
+		uncovered = true
+		for _, g := range groups {
+			if _, ok := last[g.name]; !ok {
+				g.write(out, 0, g.len())
+				out.flushLine()
+			}
+		}
+	}
+	for i, k := range keywords {
+		for _, r := range k.Ranges {
+			if r.Group == before && !uncovered {
+				writeUncovered()
+			}
+		}
+		// items added after last keyword of group continue the keyword
+		ranges := k.Ranges
+		added := false
+		for j, r := range k.Ranges {
+			if n := byName[r.Group].len(); last[r.Group] == i && r.To < n {
+				if !added {
+					ranges = append([]Range{}, k.Ranges...)
+					added = true
+				}
+				ranges[j].To = n
+			}
+		}
+		switch {
+		case !added && sha256.Sum256([]byte(rangesText(checkByName, k.Ranges))) == k.sum:
+			out.raw(k.Lines)
+		case changed != nil && changed(k):
+			for j, r := range k.Ranges {
+				if r != ranges[j] {
+					byName[r.Group].write(out, r.To, ranges[j].To)
+					out.flushLine()
+				}
+			}
+		default:
+			writeRows(out, k, ranges, checkByName, byName)
+		}
+	}
+	if !uncovered {
+		writeUncovered()
+	}
+}
+
+// writeRows write items of ranges instead of items of keyword. Source
+// lines of not changed item lines are written as is. If item lines are
+// not the same as source lines, then keyword is written by items with
+// comments before it.
+func writeRows(out *caseWriter, k Keyword, ranges []Range, check, groups map[string]itemGroup) {
+	var buf bytes.Buffer
+	writeRanges(&buf, groups, ranges)
+	lines := splitLines(buf.String())
+	sums := sumLines(splitLines(rangesText(check, ranges)))
+	if k.rows == nil || len(sums) != len(lines) {
+		out.raw(k.Lines[:k.Comments])
+		_, _ = out.Write(buf.Bytes())
+		out.flushLine()
+		return
+	}
+	// not changed lines at the begin and at the end
+	begin := 0
+	for begin < len(sums) && begin < len(k.rows) && sums[begin] == k.rows[begin] {
+		begin++
+	}
+	end := 0
+	for begin+end < len(sums) && begin+end < len(k.rows) &&
+		sums[len(sums)-1-end] == k.rows[len(k.rows)-1-end] {
+		end++
+	}
+	writeChanged := func() {
+		for _, line := range lines[begin : len(lines)-end] {
+			fmt.Fprintf(out, "%s\n", line)
+		}
+	}
+	row := 0
+	for _, line := range k.Lines {
+		if isComment(line) {
+			out.raw([]string{line})
+			continue
+		}
+		if row == begin {
+			writeChanged()
+		}
+		if row < begin || len(k.rows)-end <= row {
+			out.raw([]string{line})
+		}
+		row++
+	}
+	if row == begin {
+		writeChanged()
+	}
+}
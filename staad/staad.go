@@ -20,8 +20,9 @@ type Beam struct {
 
 // Shell - staad triangle or quadroelement
 type Shell struct {
-	Index  int
-	IPoint []int
+	Index     int
+	IPoint    []int
+	Thickness float64 // zero if thickness is not defined
 }
 
 // Format - summary format of staad data
@@ -81,6 +82,18 @@ func (std Format) String() string {
 			s = fmt.Sprintf("%s ;", s)
 			lines = append(lines, s)
 		}
+		var property []string
+		for _, p := range std.Shells {
+			if p.Thickness == 0 {
+				continue
+			}
+			property = append(property,
+				fmt.Sprintf("%v THICKNESS %.10e", p.Index, p.Thickness))
+		}
+		if len(property) != 0 {
+			lines = append(lines, "ELEMENT PROPERTY")
+			lines = append(lines, property...)
+		}
 	}
 
 	// finish
@@ -0,0 +1,476 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Submodel
+//
+// Examples:
+//
+//	*SUBMODEL,TYPE=NODE,INPUT=beamf.frd
+//	Nall
+//
+//	*SUBMODEL,TYPE=NODE,INPUT=twobeam.frd,GLOBAL ELSET=Ed1
+//	Nd1
+//
+// First line:
+//
+//	*SUBMODEL
+//	Enter the parameters TYPE (NODE or SURFACE) and INPUT and their
+//	values and, if necessary, the parameter GLOBAL ELSET.
+//
+// Following line:
+//
+//	Node numbers or node sets for TYPE=NODE,
+//	element face surfaces for TYPE=SURFACE.
+type Submodel struct {
+	Type        string // NODE or SURFACE
+	Input       string // name of global results file
+	GlobalElset string
+	Sets        []string
+}
+
+func (s Submodel) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*SUBMODEL, TYPE=%s, INPUT=%s", s.Type, s.Input)
+	if s.GlobalElset != "" {
+		fmt.Fprintf(&buf, ", GLOBAL ELSET=%s", s.GlobalElset)
+	}
+	fmt.Fprintf(&buf, "\n")
+	for _, set := range s.Sets {
+		fmt.Fprintf(&buf, "%s\n", set)
+	}
+	return buf.String()
+}
+
+func (f *Model) parseSubmodel(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SUBMODEL") {
+		return false, nil
+	}
+	var s Submodel
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "TYPE":
+			s.Type = value
+		case "INPUT":
+			s.Input = value
+		case "GLOBAL ELSET":
+			s.GlobalElset = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid submodel parameter: %s", part)
+			return
+		}
+	}
+	if s.Type != "NODE" && s.Type != "SURFACE" {
+		err = fmt.Errorf("not valid submodel type: %s", s.Type)
+		return
+	}
+	if s.Input == "" {
+		err = fmt.Errorf("submodel must have parameter INPUT")
+		return
+	}
+	for _, line := range block[1:] {
+		for _, field := range fields(line) {
+			if field != "" {
+				s.Sets = append(s.Sets, field)
+			}
+		}
+	}
+	f.Submodels = append(f.Submodels, s)
+	return true, nil
+}
+
+// Dsload is distributed load on surface.
+//
+// Examples:
+//
+//	*DSLOAD
+//	S1,P,0.01
+//
+//	*DSLOAD,SUBMODEL,STEP=1
+//	S1,P
+//
+// First line:
+//
+//	*DSLOAD
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, SUBMODEL, STEP, DATA SET.
+//	Block *DSLOAD,OP=NEW without following lines removes all
+//	distributed surface loads.
+//
+// Following line:
+//
+//	Surface name.
+//	Distributed load type label (P for pressure).
+//	Actual magnitude of the load (not used for SUBMODEL).
+type Dsload struct {
+	Surface   string
+	Label     string
+	Magnitude float64
+
+	DsloadOptions
+
+	block int // sequence number of source block
+}
+
+// DsloadOptions is parameters of *DSLOAD block
+type DsloadOptions struct {
+	LoadOptions
+	Submodel bool
+	Step     int
+	DataSet  int
+}
+
+func (o DsloadOptions) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s", o.LoadOptions)
+	if o.Submodel {
+		fmt.Fprintf(&buf, ", SUBMODEL")
+	}
+	if o.Step != 0 {
+		fmt.Fprintf(&buf, ", STEP=%d", o.Step)
+	}
+	if o.DataSet != 0 {
+		fmt.Fprintf(&buf, ", DATA SET=%d", o.DataSet)
+	}
+	return buf.String()
+}
+
+func (d Dsload) line() string {
+	if d.Submodel && d.Magnitude == 0 {
+		return fmt.Sprintf("%s, %s", d.Surface, d.Label)
+	}
+	return fmt.Sprintf("%s, %s, %s", d.Surface, d.Label, efmt.Sprint(d.Magnitude))
+}
+
+// writeDsloads write distributed surface loads. Rows with same
+// parameters and of the same block are written in one *DSLOAD block.
+// Load without surface is written as block without rows.
+func writeDsloads(buf *bytes.Buffer, loads []Dsload) {
+	for i, load := range loads {
+		if i == 0 || load.DsloadOptions != loads[i-1].DsloadOptions ||
+			load.block != loads[i-1].block || loads[i-1].Surface == "" {
+			fmt.Fprintf(buf, "*DSLOAD%s\n", load.DsloadOptions)
+		}
+		if load.Surface != "" {
+			fmt.Fprintf(buf, "%s\n", load.line())
+		}
+	}
+}
+
+func (s *Step) parseDsload(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*DSLOAD") {
+		return false, nil
+	}
+	var o DsloadOptions
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = o.LoadOptions.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		switch key {
+		case "SUBMODEL":
+			o.Submodel = true
+		case "STEP":
+			o.Step, err = parseInt(value)
+		case "DATA SET":
+			o.DataSet, err = parseInt(value)
+		default:
+			err = fmt.Errorf("not valid dsload parameter: %s", part)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	var number int
+	if n := len(s.Dsloads); 0 < n {
+		number = s.Dsloads[n-1].block + 1
+	}
+	if len(block) == 1 {
+		if o.Op != "NEW" {
+			return false, fmt.Errorf("not valid dsload")
+		}
+		// block without rows removes all distributed surface loads
+		s.Dsloads = append(s.Dsloads, Dsload{DsloadOptions: o, block: number})
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			return false, fmt.Errorf("not valid dsload line: %s", line)
+		}
+		load := Dsload{
+			Surface:       fs[0],
+			Label:         strings.ToUpper(fs[1]),
+			DsloadOptions: o,
+			block:         number,
+		}
+		if 2 < len(fs) && fs[2] != "" {
+			load.Magnitude, err = parseFloat(fs[2])
+			if err != nil {
+				return
+			}
+		}
+		s.Dsloads = append(s.Dsloads, load)
+	}
+	return true, nil
+}
+
+// submodelNodes return nodes of local model driven by submodel
+func (f Model) submodelNodes(s Submodel) (nodes []int, err error) {
+	for _, set := range s.Sets {
+		var list []int
+		if s.Type == "SURFACE" {
+			var surface Surface
+			if surface, err = f.surface(set); err != nil {
+				return
+			}
+			list, err = f.surfaceNodes(surface)
+		} else {
+			list, err = f.nodeLocation(set)
+		}
+		if err != nil {
+			return
+		}
+		nodes = append(nodes, list...)
+	}
+	return
+}
+
+// pointInElement return true if point is inside of solid element with
+// corners, that is defined by corner nodes only. Point on boundary of
+// element is inside.
+func pointInElement(p [3]float64, el Element, coords map[int][3]float64) (inside bool, err error) {
+	const tolerance = 1e-6
+	var tetras [][4]int
+	switch cornerNodes(el.Type) {
+	case 8:
+		tetras = tetrasHexa
+	case 6:
+		tetras = tetrasWedge
+	case 4:
+		tetras = tetrasTetra
+	}
+	if !strings.HasPrefix(el.Type, "C3D") || tetras == nil {
+		err = fmt.Errorf("element %d of type %s is not solid element", el.Index, el.Type)
+		return
+	}
+	for _, t := range tetras {
+		var ps [4][3]float64
+		for i := range ps {
+			var ok bool
+			if ps[i], ok = coords[el.Nodes[t[i]]]; !ok {
+				err = fmt.Errorf("element %d: not found node %d", el.Index, el.Nodes[t[i]])
+				return
+			}
+		}
+		volume := tetraVolume(ps[0], ps[1], ps[2], ps[3])
+		if volume == 0 {
+			continue
+		}
+		inside = true
+		for i := range ps {
+			// barycentric coordinate of point
+			q := ps
+			q[i] = p
+			if tetraVolume(q[0], q[1], q[2], q[3])/volume < -tolerance {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			return
+		}
+	}
+	return
+}
+
+// elementGrid is uniform grid of bounding boxes of elements for search
+// of elements, which may contain a point
+type elementGrid struct {
+	size     float64
+	boxes    [][2][3]float64
+	elements []Element
+	cells    map[[3]int][]int // indexes of elements in cell
+}
+
+// newElementGrid return grid of elements. Size of cell is average size
+// of element bounding boxes.
+func newElementGrid(elements []Element, coords map[int][3]float64) (g elementGrid, err error) {
+	g.elements = elements
+	g.cells = map[[3]int][]int{}
+	for _, el := range elements {
+		if len(el.Nodes) == 0 {
+			err = fmt.Errorf("element %d has not nodes", el.Index)
+			return
+		}
+		box := [2][3]float64{
+			{math.Inf(1), math.Inf(1), math.Inf(1)},
+			{math.Inf(-1), math.Inf(-1), math.Inf(-1)},
+		}
+		for _, n := range el.Nodes {
+			p, ok := coords[n]
+			if !ok {
+				err = fmt.Errorf("element %d: not found node %d", el.Index, n)
+				return
+			}
+			for i := range p {
+				box[0][i] = math.Min(box[0][i], p[i])
+				box[1][i] = math.Max(box[1][i], p[i])
+			}
+		}
+		for i := 0; i < 3; i++ {
+			g.size += (box[1][i] - box[0][i]) / float64(3*len(elements))
+		}
+		g.boxes = append(g.boxes, box)
+	}
+	if g.size == 0 {
+		g.size = 1
+	}
+	for k, box := range g.boxes {
+		from, to := g.cell(box[0]), g.cell(box[1])
+		for x := from[0]; x <= to[0]; x++ {
+			for y := from[1]; y <= to[1]; y++ {
+				for z := from[2]; z <= to[2]; z++ {
+					c := [3]int{x, y, z}
+					g.cells[c] = append(g.cells[c], k)
+				}
+			}
+		}
+	}
+	return
+}
+
+// cell return cell of point
+func (g elementGrid) cell(p [3]float64) (c [3]int) {
+	for i := range p {
+		c[i] = int(math.Floor(p[i] / g.size))
+	}
+	return
+}
+
+// candidates return elements with bounding box around point
+func (g elementGrid) candidates(p [3]float64) (elements []Element) {
+	tolerance := 1e-6 * g.size
+	for _, k := range g.cells[g.cell(p)] {
+		inside := true
+		for i := range p {
+			if p[i] < g.boxes[k][0][i]-tolerance || g.boxes[k][1][i]+tolerance < p[i] {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			elements = append(elements, g.elements[k])
+		}
+	}
+	return
+}
+
+// SubmodelGlobalElements return global element, which contains each
+// node of local model driven by *SUBMODEL. Key of map is local node and
+// value is global element. If the parameter GLOBAL ELSET is used, then
+// only elements of that set are used. Geometry of global elements is
+// defined by corner nodes only. Error is returned, if any driven node
+// is outside of global mesh.
+//
+// Results of global model are not checked, if results is nil. Otherwise
+// for TYPE=NODE displacements of all nodes of global element and for
+// TYPE=SURFACE stresses of global element must be in results.
+func (f Model) SubmodelGlobalElements(global Model, results *Dat) (elements map[int]int, err error) {
+	if len(f.Submodels) == 0 {
+		err = fmt.Errorf("submodel is not defined")
+		return
+	}
+	local := f.nodeCoordinates()
+	coords := global.nodeCoordinates()
+	byIndex := global.elementByIndex()
+	displaced := map[int]bool{}
+	stressed := map[int]bool{}
+	if results != nil {
+		for _, r := range results.Displacements {
+			displaced[r.Node] = true
+		}
+		for _, s := range results.Stresses {
+			stressed[s.Node] = true // element of stress
+		}
+	}
+	elements = map[int]int{}
+	for _, s := range f.Submodels {
+		var candidates []Element
+		if s.GlobalElset != "" {
+			var indexes []int
+			if indexes, err = global.ElsetIndexes(s.GlobalElset); err != nil {
+				return
+			}
+			for _, index := range indexes {
+				if el, ok := byIndex[index]; ok {
+					candidates = append(candidates, el)
+				}
+			}
+		} else {
+			for _, el := range global.Elements {
+				if strings.HasPrefix(el.Type, "C3D") {
+					candidates = append(candidates, el)
+				}
+			}
+		}
+		var grid elementGrid
+		if grid, err = newElementGrid(candidates, coords); err != nil {
+			return
+		}
+		var nodes []int
+		if nodes, err = f.submodelNodes(s); err != nil {
+			return
+		}
+		for _, n := range nodes {
+			p, ok := local[n]
+			if !ok {
+				err = fmt.Errorf("not found local node %d", n)
+				return
+			}
+			found := false
+			for _, el := range grid.candidates(p) {
+				if found, err = pointInElement(p, el, coords); err != nil {
+					return
+				}
+				if found {
+					elements[n] = el.Index
+					break
+				}
+			}
+			if !found {
+				err = fmt.Errorf("local node %d is outside of global mesh", n)
+				return
+			}
+			if results == nil {
+				continue
+			}
+			el := byIndex[elements[n]]
+			if s.Type == "SURFACE" {
+				if !stressed[el.Index] {
+					err = fmt.Errorf("not found stresses of global element %d", el.Index)
+					return
+				}
+				continue
+			}
+			for _, gn := range el.Nodes {
+				if !displaced[gn] {
+					err = fmt.Errorf("not found displacements of global node %d", gn)
+					return
+				}
+			}
+		}
+	}
+	return
+}
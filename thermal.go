@@ -0,0 +1,798 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// HeatTransfer is step procedure of keywords *HEAT TRANSFER and
+// *COUPLED TEMPERATURE-DISPLACEMENT.
+//
+// Examples:
+//
+//	*HEAT TRANSFER,STEADY STATE
+//	1.,1.
+//
+//	*HEAT TRANSFER,DELTMX=50.
+//	1.e-4,.01
+//
+//	*COUPLED TEMPERATURE-DISPLACEMENT,STEADY STATE,DIRECT
+//	.1,1.
+//
+// First line:
+//
+//	*HEAT TRANSFER or *COUPLED TEMPERATURE-DISPLACEMENT
+//	Enter any needed parameters and their values:
+//	SOLVER, DIRECT, STEADY STATE, FREQUENCY, STORAGE (only *HEAT TRANSFER),
+//	DELTMX, TIME RESET, TOTAL TIME AT START.
+//
+// Second line (if FREQUENCY is not active):
+//
+//	Initial time increment.
+//	Time period of the step.
+//	Minimum time increment allowed.
+//	Maximum time increment allowed.
+//
+// Second line (if FREQUENCY is active):
+//
+//	Number of eigenmodes desired.
+type HeatTransfer struct {
+	Coupled bool // *COUPLED TEMPERATURE-DISPLACEMENT
+
+	Solver           string
+	Direct           bool
+	SteadyState      bool
+	Frequency        bool
+	Storage          string // YES or NO
+	Deltmx           float64
+	TimeReset        bool
+	TotalTimeAtStart float64
+
+	Number int // number of eigenmodes, only for FREQUENCY
+
+	TimeInc    float64
+	TimePeriod float64
+	MinInc     float64
+	MaxInc     float64
+}
+
+// Keyword return keyword of heat transfer procedure
+func (h HeatTransfer) Keyword() Procedure {
+	if h.Coupled {
+		return ProcedureCoupled
+	}
+	return ProcedureHeatTransfer
+}
+
+func (h HeatTransfer) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s", h.Keyword())
+	if h.Solver != "" {
+		fmt.Fprintf(&buf, ", SOLVER=%s", h.Solver)
+	}
+	if h.Direct {
+		fmt.Fprintf(&buf, ", DIRECT")
+	}
+	if h.SteadyState {
+		fmt.Fprintf(&buf, ", STEADY STATE")
+	}
+	if h.Frequency {
+		fmt.Fprintf(&buf, ", FREQUENCY")
+	}
+	if h.Storage != "" {
+		fmt.Fprintf(&buf, ", STORAGE=%s", h.Storage)
+	}
+	if h.Deltmx != 0 {
+		fmt.Fprintf(&buf, ", DELTMX=%s", efmt.Sprint(h.Deltmx))
+	}
+	if h.TimeReset {
+		fmt.Fprintf(&buf, ", TIME RESET")
+	}
+	if h.TotalTimeAtStart != 0 {
+		fmt.Fprintf(&buf, ", TOTAL TIME AT START=%s", efmt.Sprint(h.TotalTimeAtStart))
+	}
+	fmt.Fprintf(&buf, "\n")
+	if h.Frequency {
+		if h.Number != 0 {
+			fmt.Fprintf(&buf, "%d\n", h.Number)
+		}
+	} else if h.TimeInc != 0 || h.TimePeriod != 0 || h.MinInc != 0 || h.MaxInc != 0 {
+		fmt.Fprintf(&buf, "%s\n", floatList(h.TimeInc, h.TimePeriod, h.MinInc, h.MaxInc))
+	}
+	return buf.String()
+}
+
+func (s *Step) parseHeatTransfer(block []string) (ok bool, err error) {
+	var h HeatTransfer
+	switch {
+	case isHeader(block[0], "*HEAT TRANSFER"):
+	case isHeader(block[0], "*COUPLED TEMPERATURE-DISPLACEMENT"):
+		h.Coupled = true
+	default:
+		return false, nil
+	}
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "SOLVER":
+			h.Solver = value
+		case "DIRECT":
+			h.Direct = true
+		case "STEADY STATE":
+			h.SteadyState = true
+		case "FREQUENCY":
+			h.Frequency = true
+		case "STORAGE":
+			h.Storage = value
+		case "DELTMX":
+			h.Deltmx, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		case "TIME RESET":
+			h.TimeReset = true
+		case "TOTAL TIME AT START":
+			h.TotalTimeAtStart, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid heat transfer parameter: %s", part)
+			return
+		}
+	}
+	switch len(block) {
+	case 1:
+		// default time increments
+	case 2:
+		if h.Frequency {
+			h.Number, err = parseInt(fields(block[1])[0])
+			if err != nil {
+				return
+			}
+			break
+		}
+		var vs []float64
+		vs, err = parseFloats(block[1])
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0, 0, 0)
+		h.TimeInc, h.TimePeriod, h.MinInc, h.MaxInc = vs[0], vs[1], vs[2], vs[3]
+	default:
+		err = fmt.Errorf("not valid block: %s", strings.Join(block, "\n"))
+		return
+	}
+	s.IsHeatTransfer = true
+	s.HeatTransfer = h
+	return true, nil
+}
+
+// LoadOptions is parameters common for thermal loads.
+//
+//	OP       - MOD (default) or NEW
+//	AMPLITUDE - name of amplitude
+//	TIME DELAY - time shift of amplitude
+//	USER     - magnitude is defined by user subroutine
+type LoadOptions struct {
+	Op        string
+	Amplitude string
+	TimeDelay float64
+	User      bool
+}
+
+func (o LoadOptions) String() string {
+	var buf bytes.Buffer
+	if o.Op != "" {
+		fmt.Fprintf(&buf, ", OP=%s", o.Op)
+	}
+	if o.Amplitude != "" {
+		fmt.Fprintf(&buf, ", AMPLITUDE=%s", o.Amplitude)
+	}
+	if o.TimeDelay != 0 {
+		fmt.Fprintf(&buf, ", TIME DELAY=%s", efmt.Sprint(o.TimeDelay))
+	}
+	if o.User {
+		fmt.Fprintf(&buf, ", USER")
+	}
+	return buf.String()
+}
+
+// parse return true if parameter is load option
+func (o *LoadOptions) parse(key, value string) (ok bool, err error) {
+	switch key {
+	case "OP":
+		o.Op = value
+	case "AMPLITUDE":
+		o.Amplitude = value
+	case "TIME DELAY":
+		o.TimeDelay, err = parseFloat(value)
+	case "USER":
+		o.User = true
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// faceNumber return face number of thermal load label.
+// For example: S3 -> 3, F2FC -> 2, R4CRNU -> 4, BF -> 0.
+func faceNumber(label string) int {
+	label = strings.TrimLeft(strings.ToUpper(label), "SFR")
+	if label == "" || label[0] < '1' || '9' < label[0] {
+		return 0
+	}
+	return int(label[0] - '0')
+}
+
+// Dflux is distributed heat flux.
+//
+// Examples:
+//
+//	*DFLUX
+//	LAST,BF,7.8E2
+//
+//	*DFLUX,AMPLITUDE=A1
+//	20,S3,10.
+//
+// First line:
+//
+//	*DFLUX
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, USER.
+//
+// Following line:
+//
+//	Element number or element set label.
+//	Distributed flux type label (S1-S6 for surface flux,
+//	BF for body flux, with suffix NU for user subroutine).
+//	Actual magnitude of the load.
+type Dflux struct {
+	LoadOptions
+	Fluxes []FaceFlux
+}
+
+// FaceFlux is data line of *DFLUX
+type FaceFlux struct {
+	Element   string
+	Label     string
+	Magnitude float64
+}
+
+// Face return face number of flux or zero for body flux
+func (ff FaceFlux) Face() int {
+	return faceNumber(ff.Label)
+}
+
+func (d Dflux) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*DFLUX%s\n", d.LoadOptions)
+	for _, ff := range d.Fluxes {
+		fmt.Fprintf(&buf, "%s, %s", ff.Element, ff.Label)
+		if ff.Magnitude != 0 {
+			fmt.Fprintf(&buf, ", %s", efmt.Sprint(ff.Magnitude))
+		}
+		fmt.Fprintf(&buf, "\n")
+	}
+	return buf.String()
+}
+
+func (s *Step) parseDflux(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*DFLUX") {
+		return false, nil
+	}
+	var d Dflux
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = d.LoadOptions.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		err = fmt.Errorf("not valid dflux parameter: %s", part)
+		return
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid dflux line: %s", line)
+			return
+		}
+		ff := FaceFlux{Element: fs[0], Label: strings.ToUpper(fs[1])}
+		if 2 < len(fs) && fs[2] != "" {
+			ff.Magnitude, err = parseFloat(fs[2])
+			if err != nil {
+				return
+			}
+		}
+		d.Fluxes = append(d.Fluxes, ff)
+	}
+	s.Dfluxes = append(s.Dfluxes, d)
+	return true, nil
+}
+
+// Cflux is concentrated heat flux.
+//
+// Example:
+//
+//	*CFLUX
+//	1,11,10.
+//
+// First line:
+//
+//	*CFLUX
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, USER, ADD.
+//
+// Following line:
+//
+//	Node number or node set label.
+//	Degree of freedom (11 for heat flux).
+//	Actual magnitude of the load.
+type Cflux struct {
+	LoadOptions
+	Add    bool
+	Fluxes []NodalFlux
+}
+
+// NodalFlux is data line of *CFLUX
+type NodalFlux struct {
+	Node      string
+	Direction int
+	Magnitude float64
+}
+
+func (c Cflux) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*CFLUX%s", c.LoadOptions)
+	if c.Add {
+		fmt.Fprintf(&buf, ", ADD")
+	}
+	fmt.Fprintf(&buf, "\n")
+	for _, nf := range c.Fluxes {
+		fmt.Fprintf(&buf, "%s, %d, %s\n", nf.Node, nf.Direction, efmt.Sprint(nf.Magnitude))
+	}
+	return buf.String()
+}
+
+func (s *Step) parseCflux(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*CFLUX") {
+		return false, nil
+	}
+	var c Cflux
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = c.LoadOptions.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		if key == "ADD" {
+			c.Add = true
+			continue
+		}
+		err = fmt.Errorf("not valid cflux parameter: %s", part)
+		return
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid cflux line: %s", line)
+			return
+		}
+		nf := NodalFlux{Node: fs[0]}
+		nf.Direction, err = parseInt(fs[1])
+		if err != nil {
+			return
+		}
+		if 2 < len(fs) && fs[2] != "" {
+			nf.Magnitude, err = parseFloat(fs[2])
+			if err != nil {
+				return
+			}
+		}
+		c.Fluxes = append(c.Fluxes, nf)
+	}
+	s.Cfluxes = append(s.Cfluxes, c)
+	return true, nil
+}
+
+// Film is convective heat transfer.
+//
+// Examples:
+//
+//	*FILM
+//	20,F3,18.,2.
+//
+//	*FILM
+//	1,F1FC,12,10.
+//
+// First line:
+//
+//	*FILM
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, FILM AMPLITUDE, FILM TIME DELAY, USER.
+//
+// Following line:
+//
+//	Element number or element set label.
+//	Film flux type label (F1-F6, with suffix FC for forced convection,
+//	NU for user subroutine).
+//	Sink temperature or, for forced convection, sink node number.
+//	Film coefficient.
+type Film struct {
+	LoadOptions
+	FilmAmplitude string
+	FilmTimeDelay float64
+	Conditions    []FilmCondition
+}
+
+// FilmCondition is data line of *FILM
+type FilmCondition struct {
+	Element         string
+	Label           string
+	SinkTemperature float64
+	SinkNode        int // only for forced convection
+	Coefficient     float64
+}
+
+// Face return face number of film condition
+func (fc FilmCondition) Face() int {
+	return faceNumber(fc.Label)
+}
+
+// IsForced return true for forced convection
+func (fc FilmCondition) IsForced() bool {
+	return strings.Contains(fc.Label, "FC")
+}
+
+func (f Film) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*FILM%s", f.LoadOptions)
+	if f.FilmAmplitude != "" {
+		fmt.Fprintf(&buf, ", FILM AMPLITUDE=%s", f.FilmAmplitude)
+	}
+	if f.FilmTimeDelay != 0 {
+		fmt.Fprintf(&buf, ", FILM TIME DELAY=%s", efmt.Sprint(f.FilmTimeDelay))
+	}
+	fmt.Fprintf(&buf, "\n")
+	for _, fc := range f.Conditions {
+		if fc.IsForced() {
+			fmt.Fprintf(&buf, "%s, %s, %d, %s\n",
+				fc.Element, fc.Label, fc.SinkNode, efmt.Sprint(fc.Coefficient))
+			continue
+		}
+		fmt.Fprintf(&buf, "%s, %s, %s, %s\n", fc.Element, fc.Label,
+			efmt.Sprint(fc.SinkTemperature), efmt.Sprint(fc.Coefficient))
+	}
+	return buf.String()
+}
+
+func (s *Step) parseFilm(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*FILM") {
+		return false, nil
+	}
+	var f Film
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = f.LoadOptions.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		switch key {
+		case "FILM AMPLITUDE":
+			f.FilmAmplitude = value
+		case "FILM TIME DELAY":
+			f.FilmTimeDelay, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		default:
+			err = fmt.Errorf("not valid film parameter: %s", part)
+			return
+		}
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid film line: %s", line)
+			return
+		}
+		fc := FilmCondition{Element: fs[0], Label: strings.ToUpper(fs[1])}
+		fs = append(fs, "", "")
+		if fc.IsForced() {
+			if fs[2] != "" {
+				fc.SinkNode, err = parseInt(fs[2])
+			}
+		} else if fs[2] != "" {
+			fc.SinkTemperature, err = parseFloat(fs[2])
+		}
+		if err != nil {
+			return
+		}
+		if fs[3] != "" {
+			fc.Coefficient, err = parseFloat(fs[3])
+			if err != nil {
+				return
+			}
+		}
+		f.Conditions = append(f.Conditions, fc)
+	}
+	s.Films = append(s.Films, f)
+	return true, nil
+}
+
+// Radiate is radiative heat transfer.
+//
+// Examples:
+//
+//	*RADIATE
+//	Eall,R3CR,300.,.8
+//
+//	*RADIATE, CAVITY=1
+//	1021, R4CRNU101, -1.000, 1.0
+//
+// First line:
+//
+//	*RADIATE
+//	Enter any needed parameters and their values:
+//	OP, AMPLITUDE, TIME DELAY, RADIATION AMPLITUDE,
+//	RADIATION TIME DELAY, USER, CAVITY, ENVNODE.
+//
+// Following line:
+//
+//	Element number or element set label.
+//	Radiation flux type label (R1-R6, with suffix CR for cavity
+//	radiation, NU for user subroutine).
+//	Sink temperature.
+//	Emissivity.
+type Radiate struct {
+	LoadOptions
+	RadiationAmplitude string
+	RadiationTimeDelay float64
+	Cavity             string
+	EnvNode            bool
+	Conditions         []RadiationCondition
+}
+
+// RadiationCondition is data line of *RADIATE
+type RadiationCondition struct {
+	Element         string
+	Label           string
+	SinkTemperature float64
+	Emissivity      float64
+}
+
+// Face return face number of radiation condition
+func (rc RadiationCondition) Face() int {
+	return faceNumber(rc.Label)
+}
+
+// IsCavity return true for cavity radiation
+func (rc RadiationCondition) IsCavity() bool {
+	return strings.Contains(rc.Label, "CR")
+}
+
+func (r Radiate) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*RADIATE%s", r.LoadOptions)
+	if r.RadiationAmplitude != "" {
+		fmt.Fprintf(&buf, ", RADIATION AMPLITUDE=%s", r.RadiationAmplitude)
+	}
+	if r.RadiationTimeDelay != 0 {
+		fmt.Fprintf(&buf, ", RADIATION TIME DELAY=%s", efmt.Sprint(r.RadiationTimeDelay))
+	}
+	if r.Cavity != "" {
+		fmt.Fprintf(&buf, ", CAVITY=%s", r.Cavity)
+	}
+	if r.EnvNode {
+		fmt.Fprintf(&buf, ", ENVNODE")
+	}
+	fmt.Fprintf(&buf, "\n")
+	for _, rc := range r.Conditions {
+		fmt.Fprintf(&buf, "%s, %s, %s, %s\n", rc.Element, rc.Label,
+			efmt.Sprint(rc.SinkTemperature), efmt.Sprint(rc.Emissivity))
+	}
+	return buf.String()
+}
+
+func (s *Step) parseRadiate(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*RADIATE") {
+		return false, nil
+	}
+	var r Radiate
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		if ok, err = r.LoadOptions.parse(key, value); err != nil {
+			return
+		} else if ok || key == "" {
+			continue
+		}
+		switch key {
+		case "RADIATION AMPLITUDE":
+			r.RadiationAmplitude = value
+		case "RADIATION TIME DELAY":
+			r.RadiationTimeDelay, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		case "CAVITY":
+			r.Cavity = value
+		case "ENVNODE":
+			r.EnvNode = true
+		default:
+			err = fmt.Errorf("not valid radiate parameter: %s", part)
+			return
+		}
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid radiate line: %s", line)
+			return
+		}
+		rc := RadiationCondition{Element: fs[0], Label: strings.ToUpper(fs[1])}
+		var vs []float64
+		vs, err = parseFloats(strings.Join(fs[2:], ","))
+		if err != nil {
+			return
+		}
+		vs = append(vs, 0, 0)
+		rc.SinkTemperature, rc.Emissivity = vs[0], vs[1]
+		r.Conditions = append(r.Conditions, rc)
+	}
+	s.Radiates = append(s.Radiates, r)
+	return true, nil
+}
+
+// Conductivity types
+const (
+	ConductivityIso   = "ISO"
+	ConductivityOrtho = "ORTHO"
+	ConductivityAniso = "ANISO"
+)
+
+// Conductivity is data line of material keyword *CONDUCTIVITY
+//
+// Examples:
+//
+//	*CONDUCTIVITY
+//	50.,0.
+//
+//	*CONDUCTIVITY,TYPE=ORTHO
+//	1.E-2,25.E-4,1.E-4
+//
+// First line:
+//
+//	*CONDUCTIVITY
+//	Enter the TYPE parameter and its value, if needed
+//	(ISO, ORTHO or ANISO, default is ISO).
+//
+// Following line for TYPE=ISO:
+//
+//	κ, temperature.
+//
+// Following line for TYPE=ORTHO:
+//
+//	κ11, κ22, κ33, temperature.
+//
+// Following line for TYPE=ANISO:
+//
+//	κ11, κ22, κ33, κ12, κ13, κ23, temperature.
+type Conductivity struct {
+	Values      []float64
+	Temperature float64
+}
+
+// conductivityValues return amount of conductivity values for type
+func conductivityValues(typ string) (n int, err error) {
+	switch typ {
+	case "", ConductivityIso:
+		return 1, nil
+	case ConductivityOrtho:
+		return 3, nil
+	case ConductivityAniso:
+		return 6, nil
+	}
+	return 0, fmt.Errorf("not valid conductivity type: %s", typ)
+}
+
+func (f *Model) parseConductivity(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*CONDUCTIVITY") {
+		return false, nil
+	}
+	m := f.lastMaterial()
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "TYPE":
+			m.ConductivityType = value
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid conductivity parameter: %s", part)
+			return
+		}
+	}
+	n, err := conductivityValues(m.ConductivityType)
+	if err != nil {
+		return
+	}
+	for _, line := range block[1:] {
+		var vs []float64
+		if vs, err = parseFloats(line); err != nil {
+			return
+		}
+		vs = append(vs, make([]float64, n+1)...)
+		m.Conductivities = append(m.Conductivities, Conductivity{
+			Values:      vs[:n],
+			Temperature: vs[n],
+		})
+	}
+	return true, nil
+}
+
+// SpecificHeat is data line of material keyword *SPECIFIC HEAT
+//
+// Example:
+//
+//	*SPECIFIC HEAT
+//	446.E6
+//
+// Following line:
+//
+//	Specific heat.
+//	Temperature.
+type SpecificHeat struct {
+	Value       float64
+	Temperature float64
+}
+
+func (f *Model) parseSpecificHeat(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*SPECIFIC HEAT") {
+		return false, nil
+	}
+	if len(fields(block[0])) != 1 {
+		err = fmt.Errorf("specific heat have not parameters")
+		return
+	}
+	m := f.lastMaterial()
+	for _, line := range block[1:] {
+		var vs []float64
+		if vs, err = parseFloats(line); err != nil {
+			return
+		}
+		vs = append(vs, 0, 0)
+		m.SpecificHeats = append(m.SpecificHeats, SpecificHeat{
+			Value:       vs[0],
+			Temperature: vs[1],
+		})
+	}
+	return true, nil
+}
+
+// writeThermalProperties write *CONDUCTIVITY and *SPECIFIC HEAT of material
+func (m Material) writeThermalProperties(buf *bytes.Buffer) {
+	if 0 < len(m.Conductivities) {
+		fmt.Fprintf(buf, "*CONDUCTIVITY")
+		if m.ConductivityType != "" {
+			fmt.Fprintf(buf, ", TYPE=%s", m.ConductivityType)
+		}
+		fmt.Fprintf(buf, "\n")
+		for _, c := range m.Conductivities {
+			fmt.Fprintf(buf, "%s\n", floatList(append(append([]float64{}, c.Values...), c.Temperature)...))
+		}
+	}
+	if 0 < len(m.SpecificHeats) {
+		fmt.Fprintf(buf, "*SPECIFIC HEAT\n")
+		for _, s := range m.SpecificHeats {
+			fmt.Fprintf(buf, "%s\n", floatList(s.Value, s.Temperature))
+		}
+	}
+}
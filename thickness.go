@@ -0,0 +1,232 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// NodalThickness is line of *NODAL THICKNESS
+//
+// Examples:
+//
+//	*NODAL THICKNESS
+//	10,.3,.5
+//	Nall,0.02
+//
+// Following line:
+//
+//	Node number or node set label.
+//	Thickness (shell elements) or thickness in 1-direction (beam elements).
+//	Thickness in 2-direction (only for beam elements).
+type NodalThickness struct {
+	Node      string
+	Thickness [2]float64
+}
+
+func writeNodalThicknesses(buf *bytes.Buffer, nts []NodalThickness) {
+	if len(nts) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "*NODAL THICKNESS\n")
+	for _, nt := range nts {
+		fmt.Fprintf(buf, "%s, %s\n", nt.Node, floatList(nt.Thickness[:]...))
+	}
+}
+
+func (f *Model) parseNodalThickness(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*NODAL THICKNESS") {
+		return false, nil
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 2 {
+			err = fmt.Errorf("not valid nodal thickness line: %s", line)
+			return
+		}
+		nt := NodalThickness{Node: fs[0]}
+		var vs []float64
+		vs, err = parseFloats(strings.Join(fs[1:], ","))
+		if err != nil {
+			return
+		}
+		copy(nt.Thickness[:], vs)
+		f.NodalThicknesses = append(f.NodalThicknesses, nt)
+	}
+	return true, nil
+}
+
+// Normal is line of *NORMAL
+//
+// Example:
+//
+//	*NORMAL
+//	1,4,-0.1,1.,0.
+//
+// Following line:
+//
+//	Element number.
+//	Node number.
+//	Components of normal in global coordinates.
+type Normal struct {
+	Element   int
+	Node      int
+	Direction [3]float64
+}
+
+func writeNormals(buf *bytes.Buffer, ns []Normal) {
+	if len(ns) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "*NORMAL\n")
+	for _, n := range ns {
+		fmt.Fprintf(buf, "%d, %d, %s, %s, %s\n", n.Element, n.Node,
+			efmt.Sprint(n.Direction[0]),
+			efmt.Sprint(n.Direction[1]),
+			efmt.Sprint(n.Direction[2]))
+	}
+}
+
+func (f *Model) parseNormal(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*NORMAL") {
+		return false, nil
+	}
+	for _, line := range block[1:] {
+		fs := fields(line)
+		if len(fs) < 3 {
+			err = fmt.Errorf("not valid normal line: %s", line)
+			return
+		}
+		var n Normal
+		if n.Element, err = parseInt(fs[0]); err != nil {
+			return
+		}
+		if n.Node, err = parseInt(fs[1]); err != nil {
+			return
+		}
+		var vs []float64
+		vs, err = parseFloats(line)
+		if err != nil {
+			return
+		}
+		copy(n.Direction[:], vs[2:])
+		f.Normals = append(f.Normals, n)
+	}
+	return true, nil
+}
+
+// shellThicknesses is shell sections of elements and nodal thicknesses
+// of model prepared once for calculation of element thicknesses
+type shellThicknesses struct {
+	elements map[int]Element
+	sections map[int]ShellSection // key is element index
+	nodal    map[int]float64      // key is node index
+}
+
+// shellThicknesses return shell sections of elements and nodal
+// thicknesses. First shell section of element is used.
+func (f Model) shellThicknesses() (st shellThicknesses, err error) {
+	st.elements = f.elementByIndex()
+	st.sections = map[int]ShellSection{}
+	nodal := false
+	for _, s := range f.ShellSections {
+		var indexes []int
+		indexes, err = f.ElsetIndexes(s.Elset)
+		if err != nil {
+			return
+		}
+		for _, index := range indexes {
+			if _, ok := st.sections[index]; !ok {
+				st.sections[index] = s
+			}
+		}
+		nodal = nodal || s.NodalThickness
+	}
+	if !nodal {
+		return
+	}
+	st.nodal = map[int]float64{}
+	for _, nt := range f.NodalThicknesses {
+		var nodes []int
+		nodes, err = f.nodeLocation(nt.Node)
+		if err != nil {
+			return
+		}
+		for _, n := range nodes {
+			st.nodal[n] = nt.Thickness[0]
+		}
+	}
+	return
+}
+
+// thickness return thickness of shell element
+func (st shellThicknesses) thickness(elem int) (thickness float64, err error) {
+	el, ok := st.elements[elem]
+	if !ok {
+		err = fmt.Errorf("not found element %d", elem)
+		return
+	}
+	ss, ok := st.sections[elem]
+	if !ok {
+		err = fmt.Errorf("not found shell section for element %d", elem)
+		return
+	}
+	if !ss.NodalThickness {
+		return ss.TotalThickness(), nil
+	}
+	if len(el.Nodes) == 0 {
+		err = fmt.Errorf("element %d has not nodes", elem)
+		return
+	}
+	for _, n := range el.Nodes {
+		t, ok := st.nodal[n]
+		if !ok {
+			err = fmt.Errorf("not found nodal thickness of node %d", n)
+			return
+		}
+		thickness += t
+	}
+	return thickness / float64(len(el.Nodes)), nil
+}
+
+// ElementThickness return thickness of shell element. If shell section
+// has parameter NODAL THICKNESS, then thickness is average of nodal
+// thicknesses of element nodes, otherwise total thickness of shell
+// section is returned. For thicknesses of many elements use
+// Model.ElementThicknesses.
+func (f Model) ElementThickness(elem int) (thickness float64, err error) {
+	st, err := f.shellThicknesses()
+	if err != nil {
+		return
+	}
+	return st.thickness(elem)
+}
+
+// ElementThicknesses return thicknesses of all elements with shell
+// section. Key of map is element index. Thickness of each element is
+// calculated in the same way as in Model.ElementThickness. Elements
+// with not valid thickness are not in map and the first error is
+// returned together with thicknesses of other elements.
+func (f Model) ElementThicknesses() (thicknesses map[int]float64, err error) {
+	thicknesses = map[int]float64{}
+	st, err := f.shellThicknesses()
+	if err != nil {
+		return
+	}
+	for _, el := range f.Elements {
+		if _, ok := st.sections[el.Index]; !ok {
+			continue
+		}
+		thk, errThk := st.thickness(el.Index)
+		if errThk != nil {
+			if err == nil {
+				err = errThk
+			}
+			continue
+		}
+		thicknesses[el.Index] = thk
+	}
+	return
+}
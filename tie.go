@@ -0,0 +1,181 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// Tie
+//
+// Examples:
+//
+//	*TIE,NAME=T1
+//	NDEP,INDF
+//
+//	*TIE,NAME=T1,ADJUST=NO,POSITION TOLERANCE=0.15
+//	NDEP,INDF
+//
+//	*TIE,CYCLIC SYMMETRY,POSITION TOLERANCE=1.,NAME=T1
+//	LEFT,RIGHT
+//
+// First line:
+//
+//	*TIE
+//	Enter the parameter NAME and its value, and, if necessary,
+//	the parameters POSITION TOLERANCE, ADJUST, CYCLIC SYMMETRY,
+//	MULTISTAGE, FLUID PERIODIC or FLUID CYCLIC.
+//
+// Following line:
+//
+//	Slave surface.
+//	Master surface.
+type Tie struct {
+	Name              string
+	PositionTolerance float64 // default: 2.5 % of typical element size
+	Adjust            string  // YES (default) or NO
+	CyclicSymmetry    bool
+	Multistage        bool
+	FluidPeriodic     bool
+	FluidCyclic       bool
+	Slave             string
+	Master            string
+}
+
+func (t Tie) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*TIE, NAME=%s", t.Name)
+	if t.PositionTolerance != 0 {
+		fmt.Fprintf(&buf, ", POSITION TOLERANCE=%s", efmt.Sprint(t.PositionTolerance))
+	}
+	if t.Adjust != "" {
+		fmt.Fprintf(&buf, ", ADJUST=%s", t.Adjust)
+	}
+	if t.CyclicSymmetry {
+		fmt.Fprintf(&buf, ", CYCLIC SYMMETRY")
+	}
+	if t.Multistage {
+		fmt.Fprintf(&buf, ", MULTISTAGE")
+	}
+	if t.FluidPeriodic {
+		fmt.Fprintf(&buf, ", FLUID PERIODIC")
+	}
+	if t.FluidCyclic {
+		fmt.Fprintf(&buf, ", FLUID CYCLIC")
+	}
+	fmt.Fprintf(&buf, "\n%s, %s\n", t.Slave, t.Master)
+	return buf.String()
+}
+
+func (f *Model) parseTie(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*TIE") {
+		return false, nil
+	}
+	var t Tie
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NAME":
+			t.Name = value
+		case "POSITION TOLERANCE":
+			t.PositionTolerance, err = parseFloat(value)
+			if err != nil {
+				return
+			}
+		case "ADJUST":
+			t.Adjust = value
+		case "CYCLIC SYMMETRY":
+			t.CyclicSymmetry = true
+		case "MULTISTAGE":
+			t.Multistage = true
+		case "FLUID PERIODIC":
+			t.FluidPeriodic = true
+		case "FLUID CYCLIC":
+			t.FluidCyclic = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid tie parameter: %s", part)
+			return
+		}
+	}
+	if len(block) != 2 {
+		err = fmt.Errorf("tie %s must have one line with surfaces", t.Name)
+		return
+	}
+	fs := fields(block[1])
+	if len(fs) < 2 {
+		err = fmt.Errorf("not valid tie line: %s", block[1])
+		return
+	}
+	t.Slave, t.Master = fs[0], fs[1]
+	f.Ties = append(f.Ties, t)
+	return true, nil
+}
+
+// TieOutOfTolerance return slave nodes of tie located outside of
+// position tolerance of master surface. If position tolerance is not
+// defined, then CalculiX default 2.5 % of typical element size is used.
+// Typical element size is average length of edges of master faces.
+func (f Model) TieOutOfTolerance(t Tie) (nodes []int, err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("tie %s: %v", t.Name, err)
+		}
+	}()
+	slave, err := f.surface(t.Slave)
+	if err != nil {
+		return
+	}
+	master, err := f.surface(t.Master)
+	if err != nil {
+		return
+	}
+	slaveNodes, err := f.surfaceNodes(slave)
+	if err != nil {
+		return
+	}
+	faces, err := f.surfaceFaces(master)
+	if err != nil {
+		return
+	}
+	coords := f.nodeCoordinates()
+	corners := make([][][3]float64, len(faces))
+	var size float64
+	var edges int
+	for i, face := range faces {
+		for _, n := range face.Nodes[:face.Corners] {
+			c, ok := coords[n]
+			if !ok {
+				err = fmt.Errorf("not found node %d", n)
+				return
+			}
+			corners[i] = append(corners[i], c)
+		}
+		for k := range corners[i] {
+			size += distance(corners[i][k], corners[i][(k+1)%len(corners[i])])
+			edges++
+		}
+	}
+	tolerance := t.PositionTolerance
+	if tolerance == 0 && 0 < edges {
+		tolerance = 0.025 * size / float64(edges)
+	}
+	for _, n := range slaveNodes {
+		c, ok := coords[n]
+		if !ok {
+			err = fmt.Errorf("not found node %d", n)
+			return
+		}
+		dist := math.Inf(1)
+		for i := range corners {
+			dist = math.Min(dist, pointFaceDistance(c, corners[i]))
+		}
+		if tolerance < dist {
+			nodes = append(nodes, n)
+		}
+	}
+	return
+}
@@ -0,0 +1,166 @@
+package inp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// TimePoints is named sequence of time points
+//
+// Examples:
+//
+//	*TIME POINTS,NAME=T1
+//	.4,.6,.9
+//
+//	*TIME POINTS,NAME=T2,GENERATE
+//	0.,1.,0.25
+//
+// First line:
+//
+//	*TIME POINTS
+//	Enter the parameter NAME and its value and, if necessary,
+//	the parameter GENERATE.
+//
+// Following line, if the parameter GENERATE is not used:
+//
+//	Time points (maximum 8 entries per line).
+//
+// Following line, if the parameter GENERATE is used:
+//
+//	Starting time.
+//	End time.
+//	Time increment.
+type TimePoints struct {
+	Name     string
+	Generate bool
+	Time     []float64
+}
+
+func (tp TimePoints) String() string {
+	return tp.format(WriteOptions{})
+}
+
+func (tp TimePoints) format(o WriteOptions) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*TIME POINTS, NAME=%s", tp.Name)
+	if tp.Generate {
+		fmt.Fprintf(&buf, ", GENERATE")
+	}
+	fmt.Fprintf(&buf, "\n")
+	perLine := 8
+	if tp.Generate {
+		perLine = 3
+	}
+	for i, t := range tp.Time {
+		if o.Float == FloatDefault {
+			fmt.Fprintf(&buf, "%s", efmt.Sprint(t))
+		} else {
+			fmt.Fprintf(&buf, "%s", o.float("", t))
+		}
+		if i == len(tp.Time)-1 || (i+1)%perLine == 0 {
+			fmt.Fprintf(&buf, "\n")
+		} else {
+			fmt.Fprintf(&buf, ", ")
+		}
+	}
+	return buf.String()
+}
+
+// Times return all time points of sequence. If the parameter GENERATE is
+// used, then time points are generated from each triple of starting time,
+// end time and time increment.
+func (tp TimePoints) Times() (times []float64, err error) {
+	if !tp.Generate {
+		return append([]float64{}, tp.Time...), nil
+	}
+	if len(tp.Time)%3 != 0 {
+		err = fmt.Errorf("time points %s: generate values is not triples: %v",
+			tp.Name, tp.Time)
+		return
+	}
+	for i := 0; i < len(tp.Time); i += 3 {
+		start, end, inc := tp.Time[i], tp.Time[i+1], tp.Time[i+2]
+		if inc <= 0 || end < start {
+			err = fmt.Errorf("time points %s: not valid generate values: %v",
+				tp.Name, tp.Time[i:i+3])
+			return
+		}
+		amount := int(math.Floor((end-start)/inc + 1e-9))
+		for k := 0; k <= amount; k++ {
+			times = append(times, start+float64(k)*inc)
+		}
+	}
+	return
+}
+
+func (f *Model) parseTimePoint(block []string) (ok bool, err error) {
+	if !isHeader(block[0], "*TIME POINTS") {
+		return false, nil
+	}
+	var tp TimePoints
+	for _, part := range fields(block[0])[1:] {
+		key, value := keyValue(part)
+		switch key {
+		case "NAME":
+			tp.Name = value
+		case "GENERATE":
+			tp.Generate = true
+		case "":
+			// do nothing
+		default:
+			err = fmt.Errorf("not valid time points parameter: %s", part)
+			return
+		}
+	}
+	if tp.Name == "" {
+		err = fmt.Errorf("time points must have parameter NAME")
+		return
+	}
+	for _, line := range block[1:] {
+		for _, field := range fields(line) {
+			if field == "" {
+				continue
+			}
+			var t float64
+			t, err = parseFloat(field)
+			if err != nil {
+				return
+			}
+			tp.Time = append(tp.Time, t)
+		}
+	}
+	f.TimePoints = append(f.TimePoints, tp)
+	return true, nil
+}
+
+// CheckTimePoints return error if output request of any step refers to
+// time points sequence that is not defined.
+func (f Model) CheckTimePoints() error {
+	names := map[string]bool{}
+	for _, tp := range f.TimePoints {
+		names[tp.Name] = true
+	}
+	for i, s := range f.Steps {
+		var references []string
+		for _, prints := range [][]Print{
+			s.NodeFiles, s.ElFiles, s.NodePrints, s.ElPrints,
+			s.ElementOutputs, s.ContactFiles, s.ContactPrints,
+		} {
+			for _, pr := range prints {
+				references = append(references, pr.TimePoints)
+			}
+		}
+		for _, sp := range s.SectionPrints {
+			references = append(references, sp.TimePoints)
+		}
+		for _, name := range references {
+			if name != "" && !names[name] {
+				return fmt.Errorf("step %d: not found time points %s", i+1, name)
+			}
+		}
+	}
+	return nil
+}
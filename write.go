@@ -0,0 +1,401 @@
+package inp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Konstantin8105/efmt"
+)
+
+// FloatStyle is style of floating point values written by Model.WriteTo
+type FloatStyle int
+
+const (
+	// FloatDefault keep format of each keyword
+	FloatDefault FloatStyle = iota
+	// FloatShortest is short engineering format of package efmt
+	FloatShortest
+	// FloatFixed is exponential format with fixed precision
+	FloatFixed
+)
+
+// maxPerLine is maximal amount of entries per line by CalculiX rules
+const maxPerLine = 16
+
+// WriteOptions is formatting options of Model.WriteTo. Zero value of
+// options gives same output as Model.String.
+type WriteOptions struct {
+	// Float is style of node coordinates, temperatures, spring constants,
+	// section thicknesses, offsets and time points. Values of other
+	// keywords are always written by package efmt.
+	Float FloatStyle
+
+	// Precision is amount of digits after decimal point for style
+	// FloatFixed. Zero value means 12 digits.
+	Precision int
+
+	// LowerCase write keyword lines in lower case. Data lines are
+	// always written in upper case.
+	LowerCase bool
+
+	// PerLine is amount of entries per line in node and element sets and
+	// in element definitions. Zero value means 9 entries for sets and 16
+	// entries for elements. Maximal value is 16.
+	PerLine int
+
+	// Comments add comment header before each group of keywords
+	Comments bool
+
+	// Source write keywords in order of source input file with comments,
+	// see Model.Source. Keywords without changes are written as in
+	// source. Lines of changed keyword are written as in source, if
+	// lines of items are not changed. Option Comments is not used.
+	Source bool
+}
+
+// float return formatted value. Format def is used for style FloatDefault.
+func (o WriteOptions) float(def string, v float64) string {
+	switch o.Float {
+	case FloatShortest:
+		return efmt.Sprint(v)
+	case FloatFixed:
+		precision := o.Precision
+		if precision == 0 {
+			precision = 12
+		}
+		return fmt.Sprintf("%.*e", precision, v)
+	}
+	return fmt.Sprintf(def, v)
+}
+
+// perLine return amount of entries per line or def for zero value
+func (o WriteOptions) perLine(def int) int {
+	if o.PerLine == 0 {
+		return def
+	}
+	return o.PerLine
+}
+
+// caseWriter write output line by line with changing case of letters
+// and count of written bytes. First error is stored and all next
+// writes are ignored.
+type caseWriter struct {
+	w         *bufio.Writer
+	lowerCase bool
+	line      []byte
+	header    string // comment header written before next line
+	n         int64
+	err       error
+}
+
+func (c *caseWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		c.line = append(c.line, b)
+		if b == '\n' {
+			c.flushLine()
+		}
+	}
+	return len(p), c.err
+}
+
+func (c *caseWriter) flushLine() {
+	if len(c.line) == 0 || c.err != nil {
+		c.line = c.line[:0]
+		return
+	}
+	line := string(c.line)
+	c.line = c.line[:0]
+	if c.header != "" {
+		c.write(c.header)
+		c.header = ""
+	}
+	switch {
+	case strings.HasPrefix(line, "**"):
+		// comments are written as is
+	case strings.HasPrefix(line, "*"):
+		line = keywordCase(line, c.lowerCase)
+	default:
+		line = strings.ToUpper(line)
+	}
+	c.write(line)
+}
+
+// caseSensitive is parameters of keywords with case sensitive values,
+// for example name of file
+var caseSensitive = map[string]bool{
+	"INPUT": true,
+}
+
+// keywordCase return keyword line in upper or lower case. Values of
+// case sensitive parameters are not changed.
+func keywordCase(line string, lowerCase bool) string {
+	change := strings.ToUpper
+	if lowerCase {
+		change = strings.ToLower
+	}
+	parts := strings.Split(line, ",")
+	for i, part := range parts {
+		key, _ := keyValue(part)
+		if index := strings.Index(part, "="); 0 < index && caseSensitive[strings.ToUpper(key)] {
+			parts[i] = change(part[:index]) + part[index:]
+			continue
+		}
+		parts[i] = change(part)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (c *caseWriter) write(s string) {
+	if c.err != nil {
+		return
+	}
+	var n int
+	n, c.err = c.w.WriteString(s)
+	c.n += int64(n)
+}
+
+// raw write lines as is without changing case
+func (c *caseWriter) raw(lines []string) {
+	c.flushLine()
+	for _, line := range lines {
+		c.write(line + "\n")
+	}
+}
+
+// WriteTo write model in format of CalculiX input file. Output is
+// streamed into writer by lines.
+func (f Model) WriteTo(w io.Writer, o WriteOptions) (n int64, err error) {
+	if o.PerLine < 0 || maxPerLine < o.PerLine {
+		err = fmt.Errorf("not valid amount of entries per line: %d", o.PerLine)
+		return
+	}
+	if o.Precision < 0 {
+		err = fmt.Errorf("not valid precision: %d", o.Precision)
+		return
+	}
+	out := &caseWriter{w: bufio.NewWriter(w), lowerCase: o.LowerCase}
+	if o.Source {
+		f.writeSource(out, o)
+	} else {
+		var header string
+		for _, g := range f.itemGroups(o) {
+			if g.header != header {
+				header = g.header
+				out.header = ""
+				if o.Comments {
+					out.header = fmt.Sprintf("** %s\n", header)
+				}
+			}
+			g.write(out, 0, g.len())
+			out.flushLine()
+		}
+	}
+	if out.err == nil {
+		out.err = out.w.Flush()
+	}
+	return out.n, out.err
+}
+
+// itemGroup is items of model produced by one keyword, for example
+// nodes of *NODE or materials of *MATERIAL. Group without length is
+// single value of model, for example *HEADING.
+type itemGroup struct {
+	name   string // name of keyword
+	header string // comment header of group in output
+	length func() int
+	write  func(w io.Writer, from, to int)
+}
+
+// len return amount of items in group
+func (g itemGroup) len() int {
+	if g.length == nil {
+		return 1
+	}
+	return g.length()
+}
+
+// items return group of slice items, written by function write
+func items[T any](name, header string, vs *[]T, write func(w io.Writer, vs []T)) itemGroup {
+	return itemGroup{
+		name:   name,
+		header: header,
+		length: func() int { return len(*vs) },
+		write: func(w io.Writer, from, to int) {
+			if len(*vs) < to {
+				to = len(*vs)
+			}
+			if to < from {
+				from = to
+			}
+			write(w, (*vs)[from:to])
+		},
+	}
+}
+
+// stringers write all values
+func stringers[T fmt.Stringer](w io.Writer, vs []T) {
+	for _, v := range vs {
+		fmt.Fprintf(w, "%s", v)
+	}
+}
+
+// single return group of single value
+func single(name, header string, v fmt.Stringer) itemGroup {
+	return itemGroup{
+		name:   name,
+		header: header,
+		write: func(w io.Writer, from, to int) {
+			fmt.Fprintf(w, "%s", v)
+		},
+	}
+}
+
+// itemGroups return groups of items in order of writing
+func (f *Model) itemGroups(o WriteOptions) []itemGroup {
+	return []itemGroup{
+		{name: "HEADING", header: "HEADING", write: func(w io.Writer, from, to int) {
+			if f.Heading != "" {
+				fmt.Fprintf(w, "*Heading\n%s\n", f.Heading)
+			}
+		}},
+		items("NODE", "NODES", &f.Nodes, func(w io.Writer, vs []Node) {
+			writeNodes(w, vs, o)
+		}),
+		items("ELEMENT", "ELEMENTS", &f.Elements, func(w io.Writer, vs []Element) {
+			writeElements(w, vs, o)
+		}),
+		items("NSET", "SETS", &f.Nsets, func(w io.Writer, vs []Set) {
+			writeSet(w, "NSET", vs, o.perLine(9))
+		}),
+		items("ELSET", "SETS", &f.Elsets, func(w io.Writer, vs []Set) {
+			writeSet(w, "ELSET", vs, o.perLine(9))
+		}),
+		items("SURFACE", "SURFACES", &f.Surfaces, stringers[Surface]),
+		items("INITIAL CONDITIONS", "INITIAL CONDITIONS", &f.Conditions, func(w io.Writer, vs []Condition) {
+			for _, c := range vs {
+				fmt.Fprintf(w, "%s", c.format(o))
+			}
+		}),
+		items("ORIENTATION", "ORIENTATIONS", &f.Orientations, stringers[Orientation]),
+		items("TRANSFORM", "ORIENTATIONS", &f.Transforms, stringers[Transform]),
+		items("SOLID SECTION", "SECTIONS", &f.SolidSections, stringers[SolidSection]),
+		items("SHELL SECTION", "SECTIONS", &f.ShellSections, func(w io.Writer, vs []ShellSection) {
+			for _, s := range vs {
+				fmt.Fprintf(w, "%s", s.format(o))
+			}
+		}),
+		items("NODAL THICKNESS", "SECTIONS", &f.NodalThicknesses, func(w io.Writer, vs []NodalThickness) {
+			var buf bytes.Buffer
+			writeNodalThicknesses(&buf, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("NORMAL", "SECTIONS", &f.Normals, func(w io.Writer, vs []Normal) {
+			var buf bytes.Buffer
+			writeNormals(&buf, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("BEAM SECTION", "SECTIONS", &f.BeamSections, func(w io.Writer, vs []BeamSection) {
+			for _, s := range vs {
+				fmt.Fprintf(w, "%s", s.format(o))
+			}
+		}),
+		items("FLUID SECTION", "SECTIONS", &f.FluidSections, stringers[FluidSection]),
+		items("SPRING", "DISCRETE ELEMENTS", &f.Springs, func(w io.Writer, vs []Spring) {
+			for _, s := range vs {
+				fmt.Fprintf(w, "%s", s.format(o))
+			}
+		}),
+		items("MASS", "DISCRETE ELEMENTS", &f.Masses, stringers[Mass]),
+		items("ROTARY INERTIA", "DISCRETE ELEMENTS", &f.RotaryInertias, stringers[RotaryInertia]),
+		items("DASHPOT", "DISCRETE ELEMENTS", &f.Dashpots, stringers[Dashpot]),
+		items("TIME POINTS", "TIME POINTS", &f.TimePoints, func(w io.Writer, vs []TimePoints) {
+			for _, tp := range vs {
+				fmt.Fprintf(w, "%s", tp.format(o))
+			}
+		}),
+		items("BOUNDARY", "BOUNDARIES", &f.Boundaries, func(w io.Writer, vs []Boundary) {
+			var buf bytes.Buffer
+			writeBoundaries(&buf, vs)
+			fmt.Fprintf(w, "%s", buf.String())
+		}),
+		items("RIGID BODY", "CONSTRAINTS", &f.RigidBodies, stringers[RigidBody]),
+		items("DISTRIBUTING COUPLING", "CONSTRAINTS", &f.DistributingCouplings, stringers[DistributingCoupling]),
+		items("COUPLING", "CONSTRAINTS", &f.Couplings, stringers[Coupling]),
+		items("GAP", "CONTACTS", &f.Gaps, stringers[Gap]),
+		items("CONTACT PAIR", "CONTACTS", &f.ContactPairs, stringers[ContactPair]),
+		items("SURFACE INTERACTION", "CONTACTS", &f.SurfaceInteractions, stringers[SurfaceInteraction]),
+		items("TIE", "CONTACTS", &f.Ties, stringers[Tie]),
+		items("CYCLIC SYMMETRY MODEL", "CYCLIC SYMMETRY", &f.CyclicSymmetryModels, stringers[CyclicSymmetryModel]),
+		single("DESIGN VARIABLES", "DESIGN VARIABLES", &f.DesignVariables),
+		items("MATERIAL", "MATERIALS", &f.Materials, stringers[Material]),
+		items("SUBMODEL", "SUBMODELS", &f.Submodels, stringers[Submodel]),
+		single("PHYSICAL CONSTANTS", "CONTROLS", &f.PhysicalConstants),
+		single("RESTART", "CONTROLS", &f.Restart),
+		items("STEP", "STEPS", &f.Steps, func(w io.Writer, vs []Step) {
+			for _, s := range vs {
+				fmt.Fprintf(w, "%s", s.format(o))
+			}
+		}),
+	}
+}
+
+// writeNodes write nodes. Nodes with same node set are written in one
+// *NODE block.
+func writeNodes(w io.Writer, nodes []Node, o WriteOptions) {
+	for pos, node := range nodes {
+		if pos == 0 || node.Nodeset != nodes[pos-1].Nodeset {
+			fmt.Fprintf(w, "*NODE")
+			if node.Nodeset != "" {
+				fmt.Fprintf(w, ",NSET=%s", node.Nodeset)
+			}
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "%5d, %s, %s, %s\n", node.Index,
+			o.float("%+.12e", node.Coord[0]),
+			o.float("%+.12e", node.Coord[1]),
+			o.float("%+.12e", node.Coord[2]))
+	}
+}
+
+// writeElements write elements. Elements with same type and element set
+// are written in one *ELEMENT block. Element line is continued on next
+// line after comma at the end of line.
+func writeElements(w io.Writer, elements []Element, o WriteOptions) {
+	perLine := o.perLine(maxPerLine)
+	var last *Element
+	for pos := range elements {
+		el := elements[pos]
+		if len(el.Nodes) == 0 {
+			continue
+		}
+		if last == nil || last.Type != el.Type || last.Elset != el.Elset {
+			fmt.Fprintf(w, "*ELEMENT")
+			if el.Type != "" {
+				fmt.Fprintf(w, ", type=%s", el.Type)
+			}
+			if el.Elset != "" {
+				fmt.Fprintf(w, ", ELSET=%s", el.Elset)
+			}
+			fmt.Fprintf(w, "\n")
+		}
+		last = &elements[pos]
+		entries := append([]int{el.Index}, el.Nodes...)
+		for i, v := range entries {
+			if 0 < i {
+				fmt.Fprintf(w, " ")
+			}
+			fmt.Fprintf(w, "%5d", v)
+			switch {
+			case i == len(entries)-1:
+				fmt.Fprintf(w, "\n")
+			case (i+1)%perLine == 0:
+				fmt.Fprintf(w, ",\n")
+			default:
+				fmt.Fprintf(w, ",")
+			}
+		}
+	}
+}